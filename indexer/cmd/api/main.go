@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/api"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/config"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/store"
+)
+
+// allServices is the full set of namespaces this binary knows how to
+// register, keyed by namespace name. cfg.Server.Namespaces selects a
+// subset; an empty list registers all of them.
+func allServices(svc *api.Service, subSvc *api.SubscriptionService) map[string]interface{} {
+	return map[string]interface{}{
+		"yfa": struct {
+			*api.Service
+			*api.SubscriptionService
+		}{svc, subSvc},
+	}
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		log.Println("Received shutdown signal")
+		cancel()
+	}()
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("loading config: %v", err)
+	}
+
+	db, err := store.Open(cfg.Database.PostgresDSN)
+	if err != nil {
+		log.Fatalf("opening store: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Migrate(ctx); err != nil {
+		log.Fatalf("migrating store: %v", err)
+	}
+
+	hub := api.NewHub()
+	svc := api.NewService(db)
+	subSvc := api.NewSubscriptionService(hub)
+
+	namespaces := cfg.Server.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{"yfa"}
+	}
+	registered := allServices(svc, subSvc)
+
+	var services []api.NamespaceService
+	for _, ns := range namespaces {
+		impl, ok := registered[ns]
+		if !ok {
+			log.Fatalf("unknown namespace %q in server.namespaces", ns)
+		}
+		services = append(services, api.NamespaceService{Namespace: ns, Service: impl})
+	}
+
+	server, err := api.NewServer(cfg.Server, services)
+	if err != nil {
+		log.Fatalf("building api server: %v", err)
+	}
+
+	poller := api.NewPoller(db, hub)
+	go poller.Run(ctx)
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := server.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		log.Printf("api server error: %v", err)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutting down api server: %v", err)
+	}
+}
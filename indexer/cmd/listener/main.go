@@ -40,7 +40,7 @@ func main() {
 	// Your original print logic
 	for _, chain := range cfg.Chains {
 		fmt.Printf("Chain Name: %s, Chain ID: %d, Start Block: %d\n", chain.Name, chain.ChainID, chain.StartBlock)
-		fmt.Printf("  RPC HTTP: %s\n", chain.RpcHttpEndpoint)
+		fmt.Printf("  RPC HTTP endpoints: %v\n", chain.RpcHttpEndpoints)
 		// ... add any other fields you want to check
 	}
 }
@@ -7,143 +7,446 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/adapter"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/backfill"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/chain"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/config"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/indexer"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/store"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/tracer"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-const (
-	ARBITRUM_RPC        = "http://127.0.0.1:8545"
-	ABIFilePath         = "strategyVault.json"
-	CONTRACT_HEX_ADDRESS = "0x949CA27A2E19A3d7c37eaFEC791750B685798123"
-)
+const ABIFilePath = "strategyVault.json"
 
 var (
 	DepositEventSig       = crypto.Keccak256Hash([]byte("Deposit(address,address,uint256,uint256)"))
 	WithdrawEventSig      = crypto.Keccak256Hash([]byte("Withdraw(address,address,address,uint256,uint256)"))
 	FeesCollectedEventSig = crypto.Keccak256Hash([]byte("FeesCollected(uint256)"))
 	PausedEventSig        = crypto.Keccak256Hash([]byte("Paused()"))
+
+	vaultTopics = []common.Hash{DepositEventSig, WithdrawEventSig, FeesCollectedEventSig, PausedEventSig}
 )
 
-func startListening(ctx context.Context) {
-	client, err := ethclient.Dial(ARBITRUM_RPC)
-	if err != nil {
-		log.Fatalf("dialing eth client: %w", err)
+// collectAddresses returns every vault and adapter address configured for
+// a chain, which is the set the indexer engine filters logs against.
+func collectAddresses(chainCfg config.ChainConfig) []common.Address {
+	var addrs []common.Address
+	for _, protocol := range chainCfg.Protocols {
+		addrs = append(addrs, common.HexToAddress(protocol.VaultAddress))
+		for _, adapter := range protocol.Adapters {
+			addrs = append(addrs, common.HexToAddress(adapter.Address))
+		}
 	}
-	defer client.Close()
+	return addrs
+}
 
-	chainID, err := client.ChainID(ctx)
-	if err != nil {
-		log.Fatalf("getting chain ID: %w", err)
+// adapterEntry pairs a built Adapter with the protocol/vault it was
+// configured under, so a matched log or periodic snapshot can be
+// attributed back to the right store rows.
+type adapterEntry struct {
+	impl         adapter.Adapter
+	protocol     string
+	vaultAddress string
+}
+
+// vaultProtocols maps a configured vault contract address to its protocol
+// name, so a decoded Deposit/Withdraw log can be attributed to a
+// protocol when persisted.
+func vaultProtocols(chainCfg config.ChainConfig) map[common.Address]string {
+	protocols := make(map[common.Address]string, len(chainCfg.Protocols))
+	for _, protocol := range chainCfg.Protocols {
+		protocols[common.HexToAddress(protocol.VaultAddress)] = protocol.Name
 	}
-	log.Printf("Connected to chain: %s", chainID.String())
+	return protocols
+}
 
-	abiData, err := os.ReadFile(ABIFilePath)
+// buildAdapters builds every adapter configured on chainCfg's protocols
+// via registry, returning them keyed by contract address alongside the
+// union of topics they want filtered. An adapterCfg with PluginPath set
+// loads out of process via registry.BuildOutOfProcess instead of the
+// in-process registry keyed by Name.
+func buildAdapters(chainCfg config.ChainConfig, registry *adapter.Registry) (map[common.Address]adapterEntry, []common.Hash) {
+	adapters := make(map[common.Address]adapterEntry)
+	topicSet := make(map[common.Hash]struct{})
+
+	for _, protocol := range chainCfg.Protocols {
+		for _, adapterCfg := range protocol.Adapters {
+			build := registry.Build
+			if adapterCfg.PluginPath != "" {
+				build = func(cfg config.AdapterConfig) (adapter.Adapter, error) {
+					return registry.BuildOutOfProcess(cfg, cfg.PluginPath)
+				}
+			}
+			impl, err := build(adapterCfg)
+			if err != nil {
+				log.Printf("chain %s: adapter %s: %v", chainCfg.Name, adapterCfg.Name, err)
+				continue
+			}
+			adapters[common.HexToAddress(adapterCfg.Address)] = adapterEntry{
+				impl:         impl,
+				protocol:     protocol.Name,
+				vaultAddress: protocol.VaultAddress,
+			}
+			for _, topic := range impl.Topics() {
+				topicSet[topic] = struct{}{}
+			}
+		}
+	}
+
+	topics := make([]common.Hash, 0, len(topicSet))
+	for topic := range topicSet {
+		topics = append(topics, topic)
+	}
+	return adapters, topics
+}
+
+// buildVaultSelfAdapters returns a snapshot-only adapterEntry, keyed by
+// vault address, for every protocol configured with no Adapters of its
+// own, treating the vault itself as a self-accounting ERC-4626 contract.
+func buildVaultSelfAdapters(chainCfg config.ChainConfig, contractABI abi.ABI) map[common.Address]adapterEntry {
+	entries := make(map[common.Address]adapterEntry)
+	for _, protocol := range chainCfg.Protocols {
+		if len(protocol.Adapters) > 0 {
+			continue
+		}
+		vaultAddress := common.HexToAddress(protocol.VaultAddress)
+		entries[vaultAddress] = adapterEntry{
+			impl:         adapter.NewSelfAdapter(protocol.Name, vaultAddress, contractABI),
+			protocol:     protocol.Name,
+			vaultAddress: protocol.VaultAddress,
+		}
+	}
+	return entries
+}
+
+// snapshotAdapters reads Position from every entry in adapters via client
+// and persists it as a VaultSnapshot, giving the API server's TVL/price
+// endpoints a periodic reading independent of adapter event traffic.
+func snapshotAdapters(ctx context.Context, chainCfg config.ChainConfig, db *store.Store, adapters map[common.Address]adapterEntry, connector chain.IChainConnector) {
+	latest, err := connector.BlockNumber(ctx)
 	if err != nil {
-		log.Fatalf("reading ABI file %s: %v", ABIFilePath, err)
+		log.Printf("[%s] snapshotting: reading latest block: %v", chainCfg.Name, err)
+		return
 	}
-	log.Printf("Loaded ABI from %s", ABIFilePath)
+	blockNumber := new(big.Int).SetUint64(latest)
 
-	contractABI, err := abi.JSON(strings.NewReader(string(abiData)))
+	for _, entry := range adapters {
+		pos, err := entry.impl.Snapshot(ctx, connector.GetClient(), blockNumber)
+		if err != nil {
+			log.Printf("[%s] adapter %s: snapshotting: %v", chainCfg.Name, entry.impl.Key(), err)
+			continue
+		}
+		snap := store.VaultSnapshot{
+			Chain:         chainCfg.Name,
+			Protocol:      entry.protocol,
+			VaultAddress:  entry.vaultAddress,
+			BlockNumber:   pos.BlockNumber,
+			TotalAssets:   pos.TotalAssets,
+			TotalSupply:   pos.TotalSupply,
+			PricePerShare: pos.PricePerShare,
+		}
+		if err := db.InsertSnapshot(ctx, snap); err != nil {
+			log.Printf("[%s] adapter %s: persisting snapshot: %v", chainCfg.Name, entry.impl.Key(), err)
+		}
+	}
+}
+
+// defaultSnapshotInterval is how often runChainListener takes a fresh
+// adapter snapshot, independent of on-chain event traffic.
+const defaultSnapshotInterval = 5 * time.Minute
+
+// runChainListener drives the reorg-safe indexer engine for a single
+// chain until ctx is cancelled, logging each confirmed (or reverted)
+// event it emits, dispatching adapter-owned logs to their adapter, and
+// persisting decoded events/snapshots to db.
+func runChainListener(ctx context.Context, chainCfg config.ChainConfig, contractABI abi.ABI, registry *adapter.Registry, db *store.Store) {
+	connector, err := chain.NewChainConnector(chainCfg)
 	if err != nil {
-		log.Fatalf("parsing ABI: %v", err)
+		log.Printf("chain %s: %v", chainCfg.Name, err)
+		return
+	}
+	defer connector.Close()
+
+	addresses := collectAddresses(chainCfg)
+	if len(addresses) == 0 {
+		log.Printf("chain %s: no protocol/adapter addresses configured, skipping", chainCfg.Name)
+		return
+	}
+
+	adapters, adapterTopics := buildAdapters(chainCfg, registry)
+	protocols := vaultProtocols(chainCfg)
+	topics := append(append([]common.Hash{}, vaultTopics...), adapterTopics...)
+
+	var trc *tracer.Tracer
+	if chainCfg.TracingEnabled {
+		trc = tracer.New(connector.GetClient())
+	}
+
+	coordinator := backfill.NewCoordinator(chainCfg, connector, db, addresses, topics, func(l types.Log) (store.VaultEvent, bool) {
+		return decodeBackfillLog(chainCfg.Name, protocols, contractABI, l)
+	})
+	if err := coordinator.Run(ctx); err != nil {
+		log.Printf("chain %s: backfill: %v", chainCfg.Name, err)
+		return
 	}
-	log.Printf("Parsed ABI successfully")
 
-	contractAddress := common.HexToAddress(CONTRACT_HEX_ADDRESS)
-	lastBlock := big.NewInt(0)
+	// snapshotSources adds vault-self entries on top of adapters without
+	// touching the latter, so a vault-self entry never shadows handleEvent's
+	// protocol-specific formatting/tracing for its logs.
+	snapshotSources := adapters
+	if vaultSelfAdapters := buildVaultSelfAdapters(chainCfg, contractABI); len(vaultSelfAdapters) > 0 {
+		snapshotSources = make(map[common.Address]adapterEntry, len(adapters)+len(vaultSelfAdapters))
+		for addr, entry := range adapters {
+			snapshotSources[addr] = entry
+		}
+		for addr, entry := range vaultSelfAdapters {
+			snapshotSources[addr] = entry
+		}
+	}
+	if len(snapshotSources) > 0 {
+		go runSnapshotLoop(ctx, chainCfg, db, snapshotSources, connector)
+	}
+
+	engine := indexer.NewEngine(chainCfg, connector, addresses, topics)
+	go func() {
+		if err := engine.Run(ctx); err != nil {
+			log.Printf("chain %s: indexer stopped: %v", chainCfg.Name, err)
+		}
+	}()
 
+	for evt := range engine.Events() {
+		if entry, ok := adapters[evt.Log.Address]; ok {
+			handleAdapterEvent(ctx, chainCfg, db, entry, evt)
+			continue
+		}
+		handleEvent(ctx, chainCfg, db, protocols, contractABI, trc, evt)
+	}
+}
+
+// runSnapshotLoop calls snapshotAdapters on defaultSnapshotInterval until
+// ctx is cancelled, taking one snapshot immediately on start.
+func runSnapshotLoop(ctx context.Context, chainCfg config.ChainConfig, db *store.Store, adapters map[common.Address]adapterEntry, connector chain.IChainConnector) {
+	snapshotAdapters(ctx, chainCfg, db, adapters, connector)
+
+	ticker := time.NewTicker(defaultSnapshotInterval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Stopping listener")
 			return
-		default:
-			latestBlock, err := client.BlockNumber(ctx)
-			if err != nil {
-				log.Printf("Failed to get block number: %v", err)
-				time.Sleep(2 * time.Second)
-				continue
-			}
-			log.Printf("Polling blocks from %d to %d", lastBlock.Uint64(), latestBlock)
+		case <-ticker.C:
+			snapshotAdapters(ctx, chainCfg, db, adapters, connector)
+		}
+	}
+}
 
-			from := latestBlock - 499
-			to := latestBlock
-			if to-from > 499 {
-				to = from + 499
-			}
+// handleAdapterEvent logs a matched log decoded through the adapter that
+// owns its contract address and persists it as a VaultEvent.
+func handleAdapterEvent(ctx context.Context, chainCfg config.ChainConfig, db *store.Store, entry adapterEntry, evt indexer.Event) {
+	vLog := evt.Log
+	if evt.Reverted {
+		log.Printf("[%s] Reverted (%s): Tx=%s, Block=%d", chainCfg.Name, entry.impl.Key(), vLog.TxHash.Hex(), vLog.BlockNumber)
+		persistEvent(ctx, db, store.VaultEvent{
+			Chain: chainCfg.Name, Protocol: entry.protocol, VaultAddress: entry.vaultAddress,
+			EventName: entry.impl.Key(), TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index),
+			BlockNumber: vLog.BlockNumber, Reverted: true,
+		})
+		return
+	}
+	decoded, err := entry.impl.Decode(vLog)
+	if err != nil {
+		log.Printf("[%s] adapter %s: decoding log: %v", chainCfg.Name, entry.impl.Key(), err)
+		return
+	}
+	log.Printf("[%s] adapter %s: %s Tx=%s Args=%v Block=%d",
+		chainCfg.Name, entry.impl.Key(), decoded.Name, vLog.TxHash.Hex(), decoded.Args, vLog.BlockNumber)
+	persistEvent(ctx, db, store.VaultEvent{
+		Chain: chainCfg.Name, Protocol: entry.protocol, VaultAddress: entry.vaultAddress,
+		EventName: decoded.Name, TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index),
+		BlockNumber: vLog.BlockNumber,
+	})
+}
 
-			query := ethereum.FilterQuery{
-				FromBlock: big.NewInt(int64(from)),
-				ToBlock:   big.NewInt(int64(to)),
-				Addresses: []common.Address{contractAddress},
-				Topics:    [][]common.Hash{{DepositEventSig, WithdrawEventSig, FeesCollectedEventSig, PausedEventSig}},
-			}
+// decodeBackfillLog decodes a historical Deposit/Withdraw/FeesCollected
+// log into a VaultEvent. Adapter-owned logs also turn up in the scanned
+// range but aren't decodable against contractABI, so they're skipped
+// here and left to the live engine plus periodic snapshots.
+func decodeBackfillLog(chainName string, protocols map[common.Address]string, contractABI abi.ABI, vLog types.Log) (store.VaultEvent, bool) {
+	if len(vLog.Topics) == 0 {
+		return store.VaultEvent{}, false
+	}
+	protocol := protocols[vLog.Address]
 
-			logs, err := client.FilterLogs(ctx, query)
-			if err != nil {
-				log.Printf("Failed to filter logs: %v", err)
-				time.Sleep(2 * time.Second)
-				continue
-			}
-			if len(logs) == 0 {
-				log.Printf("No logs found in block range %d to %d", from, to)
-			}
+	switch vLog.Topics[0] {
+	case DepositEventSig:
+		event := struct {
+			Assets *big.Int
+			Shares *big.Int
+		}{}
+		if err := contractABI.UnpackIntoInterface(&event, "Deposit", vLog.Data); err != nil {
+			log.Printf("[%s] backfill: unpacking Deposit: %v", chainName, err)
+			return store.VaultEvent{}, false
+		}
+		caller := common.BytesToAddress(vLog.Topics[1].Bytes())
+		receiver := common.BytesToAddress(vLog.Topics[2].Bytes())
+		return store.VaultEvent{
+			Chain: chainName, Protocol: protocol, VaultAddress: vLog.Address.Hex(),
+			EventName: "Deposit", TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index), BlockNumber: vLog.BlockNumber,
+			Caller: caller.Hex(), Receiver: receiver.Hex(), Owner: receiver.Hex(),
+			Assets: event.Assets, Shares: event.Shares,
+		}, true
+	case WithdrawEventSig:
+		event := struct {
+			Assets *big.Int
+			Shares *big.Int
+		}{}
+		if err := contractABI.UnpackIntoInterface(&event, "Withdraw", vLog.Data); err != nil {
+			log.Printf("[%s] backfill: unpacking Withdraw: %v", chainName, err)
+			return store.VaultEvent{}, false
+		}
+		caller := common.BytesToAddress(vLog.Topics[1].Bytes())
+		receiver := common.BytesToAddress(vLog.Topics[2].Bytes())
+		owner := common.BytesToAddress(vLog.Topics[3].Bytes())
+		return store.VaultEvent{
+			Chain: chainName, Protocol: protocol, VaultAddress: vLog.Address.Hex(),
+			EventName: "Withdraw", TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index), BlockNumber: vLog.BlockNumber,
+			Caller: caller.Hex(), Receiver: receiver.Hex(), Owner: owner.Hex(),
+			Assets: event.Assets, Shares: event.Shares,
+		}, true
+	case FeesCollectedEventSig:
+		event := struct {
+			Amount *big.Int
+		}{}
+		if err := contractABI.UnpackIntoInterface(&event, "FeesCollected", vLog.Data); err != nil {
+			log.Printf("[%s] backfill: unpacking FeesCollected: %v", chainName, err)
+			return store.VaultEvent{}, false
+		}
+		return store.VaultEvent{
+			Chain: chainName, Protocol: protocol, VaultAddress: vLog.Address.Hex(),
+			EventName: "FeesCollected", TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index), BlockNumber: vLog.BlockNumber,
+			Assets: event.Amount,
+		}, true
+	default:
+		return store.VaultEvent{}, false
+	}
+}
 
-			for _, vLog := range logs {
-				log.Printf("Processing log: Tx=%s, Block=%d", vLog.TxHash.Hex(), vLog.BlockNumber)
-				switch vLog.Topics[0] {
-				case PausedEventSig:
-					log.Printf("Paused: Tx=%s, Block=%d, Time=%s", vLog.TxHash.Hex(), vLog.BlockNumber, time.Now().Format(time.RFC3339))
-				case DepositEventSig:
-					event := struct {
-						Assets *big.Int
-						Shares *big.Int
-					}{}
-					if err := contractABI.UnpackIntoInterface(&event, "Deposit", vLog.Data); err != nil {
-						log.Printf("Unpacking Deposit: %v", err)
-						continue
-					}
-					caller := common.BytesToAddress(vLog.Topics[1].Bytes())
-					receiver := common.BytesToAddress(vLog.Topics[2].Bytes())
-					log.Printf("Deposit: Tx=%s, Caller=%s, Receiver=%s, Assets=%s, Shares=%s, Block=%d, Time=%s",
-						vLog.TxHash.Hex(), caller.Hex(), receiver.Hex(), event.Assets.String(), event.Shares.String(), vLog.BlockNumber, time.Now().Format(time.RFC3339))
-				case WithdrawEventSig:
-					event := struct {
-						Assets *big.Int
-						Shares *big.Int
-					}{}
-					if err := contractABI.UnpackIntoInterface(&event, "Withdraw", vLog.Data); err != nil {
-						log.Printf("Unpacking Withdraw: %v", err)
-						continue
-					}
-					caller := common.BytesToAddress(vLog.Topics[1].Bytes())
-					receiver := common.BytesToAddress(vLog.Topics[2].Bytes())
-					owner := common.BytesToAddress(vLog.Topics[3].Bytes())
-					log.Printf("Withdraw: Tx=%s, Caller=%s, Receiver=%s, Owner=%s, Assets=%s, Shares=%s, Block=%d, Time=%s",
-						vLog.TxHash.Hex(), caller.Hex(), receiver.Hex(), owner.Hex(), event.Assets.String(), event.Shares.String(), vLog.BlockNumber, time.Now().Format(time.RFC3339))
-				case FeesCollectedEventSig:
-					event := struct {
-						Amount *big.Int
-					}{}
-					if err := contractABI.UnpackIntoInterface(&event, "FeesCollected", vLog.Data); err != nil {
-						log.Printf("Unpacking FeesCollected: %v", err)
-						continue
-					}
-					log.Printf("FeesCollected: Tx=%s, Amount=%s, Block=%d, Time=%s",
-						vLog.TxHash.Hex(), event.Amount.String(), vLog.BlockNumber, time.Now().Format(time.RFC3339))
-				default:
-					log.Printf("Unknown event with topic: %s", vLog.Topics[0].Hex())
-				}
-			}
+func handleEvent(ctx context.Context, chainCfg config.ChainConfig, db *store.Store, protocols map[common.Address]string, contractABI abi.ABI, trc *tracer.Tracer, evt indexer.Event) {
+	vLog := evt.Log
+	protocol := protocols[vLog.Address]
+
+	if evt.Reverted {
+		log.Printf("[%s] Reverted: Tx=%s, Block=%d", chainCfg.Name, vLog.TxHash.Hex(), vLog.BlockNumber)
+		persistEvent(ctx, db, store.VaultEvent{
+			Chain: chainCfg.Name, Protocol: protocol, VaultAddress: vLog.Address.Hex(),
+			EventName: "Reverted", TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index),
+			BlockNumber: vLog.BlockNumber, Reverted: true,
+		})
+		return
+	}
+
+	log.Printf("[%s] Processing log: Tx=%s, Block=%d", chainCfg.Name, vLog.TxHash.Hex(), vLog.BlockNumber)
+	switch vLog.Topics[0] {
+	case PausedEventSig:
+		log.Printf("[%s] Paused: Tx=%s, Block=%d", chainCfg.Name, vLog.TxHash.Hex(), vLog.BlockNumber)
+	case DepositEventSig:
+		event := struct {
+			Assets *big.Int
+			Shares *big.Int
+		}{}
+		if err := contractABI.UnpackIntoInterface(&event, "Deposit", vLog.Data); err != nil {
+			log.Printf("[%s] Unpacking Deposit: %v", chainCfg.Name, err)
+			return
+		}
+		caller := common.BytesToAddress(vLog.Topics[1].Bytes())
+		receiver := common.BytesToAddress(vLog.Topics[2].Bytes())
+		log.Printf("[%s] Deposit: Tx=%s, Caller=%s, Receiver=%s, Assets=%s, Shares=%s, Block=%d",
+			chainCfg.Name, vLog.TxHash.Hex(), caller.Hex(), receiver.Hex(), event.Assets.String(), event.Shares.String(), vLog.BlockNumber)
+		persistEvent(ctx, db, store.VaultEvent{
+			Chain: chainCfg.Name, Protocol: protocol, VaultAddress: vLog.Address.Hex(),
+			EventName: "Deposit", TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index), BlockNumber: vLog.BlockNumber,
+			Caller: caller.Hex(), Receiver: receiver.Hex(), Owner: receiver.Hex(),
+			Assets: event.Assets, Shares: event.Shares,
+		})
+		traceEvent(ctx, chainCfg, trc, vLog)
+	case WithdrawEventSig:
+		event := struct {
+			Assets *big.Int
+			Shares *big.Int
+		}{}
+		if err := contractABI.UnpackIntoInterface(&event, "Withdraw", vLog.Data); err != nil {
+			log.Printf("[%s] Unpacking Withdraw: %v", chainCfg.Name, err)
+			return
+		}
+		caller := common.BytesToAddress(vLog.Topics[1].Bytes())
+		receiver := common.BytesToAddress(vLog.Topics[2].Bytes())
+		owner := common.BytesToAddress(vLog.Topics[3].Bytes())
+		log.Printf("[%s] Withdraw: Tx=%s, Caller=%s, Receiver=%s, Owner=%s, Assets=%s, Shares=%s, Block=%d",
+			chainCfg.Name, vLog.TxHash.Hex(), caller.Hex(), receiver.Hex(), owner.Hex(), event.Assets.String(), event.Shares.String(), vLog.BlockNumber)
+		persistEvent(ctx, db, store.VaultEvent{
+			Chain: chainCfg.Name, Protocol: protocol, VaultAddress: vLog.Address.Hex(),
+			EventName: "Withdraw", TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index), BlockNumber: vLog.BlockNumber,
+			Caller: caller.Hex(), Receiver: receiver.Hex(), Owner: owner.Hex(),
+			Assets: event.Assets, Shares: event.Shares,
+		})
+		traceEvent(ctx, chainCfg, trc, vLog)
+	case FeesCollectedEventSig:
+		event := struct {
+			Amount *big.Int
+		}{}
+		if err := contractABI.UnpackIntoInterface(&event, "FeesCollected", vLog.Data); err != nil {
+			log.Printf("[%s] Unpacking FeesCollected: %v", chainCfg.Name, err)
+			return
+		}
+		log.Printf("[%s] FeesCollected: Tx=%s, Amount=%s, Block=%d", chainCfg.Name, vLog.TxHash.Hex(), event.Amount.String(), vLog.BlockNumber)
+		persistEvent(ctx, db, store.VaultEvent{
+			Chain: chainCfg.Name, Protocol: protocol, VaultAddress: vLog.Address.Hex(),
+			EventName: "FeesCollected", TxHash: vLog.TxHash.Hex(), LogIndex: int(vLog.Index), BlockNumber: vLog.BlockNumber,
+			Assets: event.Amount,
+		})
+		traceEvent(ctx, chainCfg, trc, vLog)
+	default:
+		log.Printf("[%s] Unknown event with topic: %s", chainCfg.Name, vLog.Topics[0].Hex())
+	}
+}
+
+// persistEvent writes e to db, logging (rather than failing the run) on
+// error since a dropped write shouldn't stop the indexer from processing
+// live chain data.
+func persistEvent(ctx context.Context, db *store.Store, e store.VaultEvent) {
+	if err := db.InsertEvent(ctx, e); err != nil {
+		log.Printf("persisting event %s Tx=%s: %v", e.EventName, e.TxHash, err)
+	}
+}
 
-			lastBlock.SetUint64(to + 1)
-			time.Sleep(3 * time.Second)
+// traceEvent enriches vLog's transaction with its call tree when tracing
+// is enabled for the chain, logging the internal transfers and any
+// failed sub-calls it finds.
+func traceEvent(ctx context.Context, chainCfg config.ChainConfig, trc *tracer.Tracer, vLog types.Log) {
+	if trc == nil {
+		return
+	}
+	traced, err := trc.Trace(ctx, vLog)
+	if err != nil {
+		log.Printf("[%s] tracing Tx=%s: %v", chainCfg.Name, vLog.TxHash.Hex(), err)
+		return
+	}
+	for _, transfer := range traced.InternalTransfers {
+		log.Printf("[%s] internal transfer Tx=%s Token=%s From=%s To=%s Amount=%s",
+			chainCfg.Name, vLog.TxHash.Hex(), transfer.Token.Hex(), transfer.From.Hex(), transfer.To.Hex(), transfer.Amount.String())
+	}
+	for _, sub := range traced.SubCalls {
+		if !sub.Success {
+			log.Printf("[%s] failed sub-call Tx=%s To=%s Selector=%x Error=%s",
+				chainCfg.Name, vLog.TxHash.Hex(), sub.To.Hex(), sub.Selector, sub.Error)
 		}
 	}
 }
@@ -160,5 +463,42 @@ func main() {
 		cancel()
 	}()
 
-	startListening(ctx)
-}
\ No newline at end of file
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("loading config: %v", err)
+	}
+
+	abiData, err := os.ReadFile(ABIFilePath)
+	if err != nil {
+		log.Fatalf("reading ABI file %s: %v", ABIFilePath, err)
+	}
+	contractABI, err := abi.JSON(strings.NewReader(string(abiData)))
+	if err != nil {
+		log.Fatalf("parsing ABI: %v", err)
+	}
+
+	registry := adapter.NewRegistry()
+	defer registry.Close()
+
+	db, err := store.Open(cfg.Database.PostgresDSN)
+	if err != nil {
+		log.Fatalf("opening store: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(ctx); err != nil {
+		log.Fatalf("migrating store: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, chainCfg := range cfg.Chains {
+		if !chainCfg.Enabled {
+			continue
+		}
+		wg.Add(1)
+		go func(chainCfg config.ChainConfig) {
+			defer wg.Done()
+			runChainListener(ctx, chainCfg, contractABI, registry, db)
+		}(chainCfg)
+	}
+	wg.Wait()
+}
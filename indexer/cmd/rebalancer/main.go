@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/accounts"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/chain"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/config"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/executor"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/store"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const abiFilePath = "strategyVault.json"
+
+// defaultStaleThreshold is how old a vault's last FeesCollected event can
+// get before HarvestScanner flags it.
+const defaultStaleThreshold = 24 * time.Hour
+
+// buildSigner resolves chainCfg.Signer into an accounts.Manager plus the
+// address it should act as, unlocking a keystore backend with its
+// configured passphrase file if that's the backend in use.
+func buildSigner(signerCfg config.SignerConfig) (*accounts.Manager, common.Address, error) {
+	signerAddr := common.HexToAddress(signerCfg.SignerAddress)
+
+	switch {
+	case signerCfg.KeystorePath != "":
+		backend := accounts.NewKeystoreBackend(signerCfg.KeystorePath)
+		passphrase, err := os.ReadFile(signerCfg.PassphraseFile)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		if err := backend.Unlock(signerAddr, strings.TrimSpace(string(passphrase))); err != nil {
+			return nil, common.Address{}, err
+		}
+		return accounts.NewManager(backend), signerAddr, nil
+
+	case signerCfg.ExternalSignerURL != "":
+		backend, err := accounts.NewExternalBackend(signerCfg.ExternalSignerURL)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		return accounts.NewManager(backend), signerAddr, nil
+
+	case signerCfg.HardwareWallet != "":
+		var (
+			backend *accounts.HardwareBackend
+			err     error
+		)
+		switch signerCfg.HardwareWallet {
+		case "ledger":
+			backend, err = accounts.NewLedgerBackend()
+		case "trezor":
+			backend, err = accounts.NewTrezorBackend()
+		default:
+			log.Fatalf("unknown hardware_wallet %q (want ledger or trezor)", signerCfg.HardwareWallet)
+		}
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		return accounts.NewManager(backend), signerAddr, nil
+
+	default:
+		log.Fatalf("signer configured with no keystore_path, external_signer_url, or hardware_wallet")
+		return nil, common.Address{}, nil
+	}
+}
+
+// runChainRebalancer unlocks chainCfg's signer, then runs a HarvestScanner
+// alongside an Executor that carries out every Decision it emits, until
+// ctx is cancelled.
+func runChainRebalancer(ctx context.Context, chainCfg config.ChainConfig, contractABI abi.ABI, db *store.Store) {
+	if chainCfg.Signer == nil {
+		log.Printf("chain %s: no signer configured, skipping (read-only)", chainCfg.Name)
+		return
+	}
+
+	connector, err := chain.NewChainConnector(chainCfg)
+	if err != nil {
+		log.Printf("chain %s: %v", chainCfg.Name, err)
+		return
+	}
+	defer connector.Close()
+
+	accountsMgr, signerAddr, err := buildSigner(*chainCfg.Signer)
+	if err != nil {
+		log.Printf("chain %s: building signer: %v", chainCfg.Name, err)
+		return
+	}
+
+	gasStrategy := accounts.NewGasStrategy(chainCfg.Signer.GasStrategy)
+	exec := executor.NewExecutor(chainCfg, connector, accountsMgr, signerAddr, contractABI, gasStrategy)
+
+	decisions := make(chan executor.Decision, 16)
+	scanner := executor.NewHarvestScanner(db, chainCfg.Name, defaultStaleThreshold)
+	go scanner.Run(ctx, decisions)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-decisions:
+			txHash, err := exec.Submit(ctx, d)
+			if err != nil {
+				log.Printf("[%s] executor: %s on %s failed: %v", chainCfg.Name, d.Method, d.VaultAddress.Hex(), err)
+				continue
+			}
+			log.Printf("[%s] executor: %s on %s confirmed Tx=%s", chainCfg.Name, d.Method, d.VaultAddress.Hex(), txHash.Hex())
+		}
+	}
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		log.Println("Received shutdown signal")
+		cancel()
+	}()
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("loading config: %v", err)
+	}
+
+	abiData, err := os.ReadFile(abiFilePath)
+	if err != nil {
+		log.Fatalf("reading ABI file %s: %v", abiFilePath, err)
+	}
+	contractABI, err := abi.JSON(strings.NewReader(string(abiData)))
+	if err != nil {
+		log.Fatalf("parsing ABI: %v", err)
+	}
+
+	db, err := store.Open(cfg.Database.PostgresDSN)
+	if err != nil {
+		log.Fatalf("opening store: %v", err)
+	}
+	defer db.Close()
+	if err := db.Migrate(ctx); err != nil {
+		log.Fatalf("migrating store: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, chainCfg := range cfg.Chains {
+		if !chainCfg.Enabled || chainCfg.Signer == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(chainCfg config.ChainConfig) {
+			defer wg.Done()
+			runChainRebalancer(ctx, chainCfg, contractABI, db)
+		}(chainCfg)
+	}
+	wg.Wait()
+}
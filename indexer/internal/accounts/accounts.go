@@ -0,0 +1,111 @@
+// Package accounts gives the executor a backend-agnostic way to sign
+// transactions: a Manager holds one or more Backends (keystore, external
+// signer, hardware wallet) and dispatches to whichever controls the
+// requested address.
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend is a source of signing keys. Every implementation in this
+// package (keystore, external, hardware) satisfies it.
+type Backend interface {
+	// Name identifies the backend in logs and errors, e.g. "keystore".
+	Name() string
+	// Accounts lists every address this backend can sign for.
+	Accounts() []common.Address
+	// Contains reports whether addr is one of Accounts().
+	Contains(addr common.Address) bool
+	// SignTx returns tx signed for chainID by addr's key. addr must
+	// satisfy Contains.
+	SignTx(ctx context.Context, addr common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// Unlocker is implemented by backends that hold key material locally and
+// require an explicit unlock before signing (currently: KeystoreBackend).
+// External signers and hardware wallets manage their own approval outside
+// this process, so they don't implement it.
+type Unlocker interface {
+	Unlock(addr common.Address, passphrase string) error
+	Lock(addr common.Address) error
+}
+
+// Manager aggregates every configured Backend for a chain and routes
+// Unlock/Lock/SignTx calls to whichever one controls the requested address.
+type Manager struct {
+	backends []Backend
+}
+
+// NewManager returns a Manager over backends, tried in order when more
+// than one claims the same address (which callers should avoid).
+func NewManager(backends ...Backend) *Manager {
+	return &Manager{backends: backends}
+}
+
+// find returns the first backend that claims addr.
+func (m *Manager) find(addr common.Address) (Backend, error) {
+	for _, b := range m.backends {
+		if b.Contains(addr) {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("accounts: no backend holds address %s", addr.Hex())
+}
+
+// Accounts lists every address across every configured backend.
+func (m *Manager) Accounts() []common.Address {
+	var all []common.Address
+	for _, b := range m.backends {
+		all = append(all, b.Accounts()...)
+	}
+	return all
+}
+
+// Unlock unlocks addr with passphrase. It fails if no configured backend
+// holds addr, or the backend that does isn't an Unlocker (external
+// signers and hardware wallets manage their own approval).
+func (m *Manager) Unlock(addr common.Address, passphrase string) error {
+	b, err := m.find(addr)
+	if err != nil {
+		return err
+	}
+	unlocker, ok := b.(Unlocker)
+	if !ok {
+		return fmt.Errorf("accounts: backend %s does not support Unlock", b.Name())
+	}
+	return unlocker.Unlock(addr, passphrase)
+}
+
+// Lock re-locks addr, if its backend supports it. For non-Unlocker
+// backends it is a no-op returning nil, since there's nothing left
+// unlocked to protect.
+func (m *Manager) Lock(addr common.Address) error {
+	b, err := m.find(addr)
+	if err != nil {
+		return err
+	}
+	if unlocker, ok := b.(Unlocker); ok {
+		return unlocker.Lock(addr)
+	}
+	return nil
+}
+
+// SignTx signs tx as addr against chainID, delegating to whichever
+// backend holds the key.
+func (m *Manager) SignTx(ctx context.Context, addr common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	b, err := m.find(addr)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := b.SignTx(ctx, addr, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: signing via %s: %w", b.Name(), err)
+	}
+	return signed, nil
+}
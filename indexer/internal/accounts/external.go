@@ -0,0 +1,57 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ExternalBackend signs by delegating to a running Clef instance over its
+// JSON-RPC endpoint, so the executor never holds key material itself;
+// Clef owns unlocking and interactive approval.
+type ExternalBackend struct {
+	signer *external.ExternalSigner
+}
+
+// NewExternalBackend dials the Clef instance listening at endpoint (e.g.
+// "http://localhost:8550" or a Unix socket path).
+func NewExternalBackend(endpoint string) (*ExternalBackend, error) {
+	signer, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: dialing external signer %s: %w", endpoint, err)
+	}
+	return &ExternalBackend{signer: signer}, nil
+}
+
+func (b *ExternalBackend) Name() string { return "clef-external" }
+
+func (b *ExternalBackend) Accounts() []common.Address {
+	accts := b.signer.Accounts()
+	addrs := make([]common.Address, len(accts))
+	for i, a := range accts {
+		addrs[i] = a.Address
+	}
+	return addrs
+}
+
+func (b *ExternalBackend) Contains(addr common.Address) bool {
+	for _, a := range b.Accounts() {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *ExternalBackend) SignTx(ctx context.Context, addr common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	for _, a := range b.signer.Accounts() {
+		if a.Address == addr {
+			return b.signer.SignTx(a, tx, chainID)
+		}
+	}
+	return nil, fmt.Errorf("external signer: account %s not found", addr.Hex())
+}
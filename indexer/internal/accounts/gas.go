@@ -0,0 +1,72 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/chain"
+)
+
+// GasParams carries either a legacy gas price or an EIP-1559 fee pair,
+// never both: Executor checks which is set to decide which
+// types.NewTx(...) variant to build.
+type GasParams struct {
+	GasPrice  *big.Int // legacy (types.LegacyTx)
+	GasTipCap *big.Int // EIP-1559 (types.DynamicFeeTx)
+	GasFeeCap *big.Int
+}
+
+// GasStrategy prices a transaction against the chain's current fee market.
+type GasStrategy interface {
+	Suggest(ctx context.Context, connector chain.IChainConnector) (GasParams, error)
+}
+
+// NewGasStrategy resolves a SignerConfig.GasStrategy value ("legacy" or
+// "eip1559") to a GasStrategy, defaulting to legacy for an empty or
+// unrecognized value.
+func NewGasStrategy(mode string) GasStrategy {
+	if mode == "eip1559" {
+		return dynamicFeeGasStrategy{}
+	}
+	return legacyGasStrategy{}
+}
+
+// legacyGasStrategy prices with a single gas price, via eth_gasPrice.
+type legacyGasStrategy struct{}
+
+func (legacyGasStrategy) Suggest(ctx context.Context, connector chain.IChainConnector) (GasParams, error) {
+	price, err := connector.SuggestGasPrice(ctx)
+	if err != nil {
+		return GasParams{}, fmt.Errorf("gas: suggesting legacy gas price: %w", err)
+	}
+	return GasParams{GasPrice: price}, nil
+}
+
+// feeCapMultiplier is how far above the current base fee GasFeeCap is
+// set, so a couple of base-fee increases before inclusion don't strand
+// the transaction.
+const feeCapMultiplier = 2
+
+// dynamicFeeGasStrategy prices with an EIP-1559 tip/fee-cap pair, using
+// the chain's suggested priority tip and its latest header's base fee as
+// the oracle.
+type dynamicFeeGasStrategy struct{}
+
+func (dynamicFeeGasStrategy) Suggest(ctx context.Context, connector chain.IChainConnector) (GasParams, error) {
+	tip, err := connector.SuggestGasTipCap(ctx)
+	if err != nil {
+		return GasParams{}, fmt.Errorf("gas: suggesting priority tip: %w", err)
+	}
+
+	head, err := connector.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return GasParams{}, fmt.Errorf("gas: reading latest header for base fee: %w", err)
+	}
+	if head.BaseFee == nil {
+		return GasParams{}, fmt.Errorf("gas: chain does not report a base fee (pre-EIP-1559?)")
+	}
+
+	feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(feeCapMultiplier)), tip)
+	return GasParams{GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
@@ -0,0 +1,84 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HardwareBackend signs with a Ledger or Trezor reached over USB, via
+// go-ethereum's accounts/usbwallet driver. Wallets are opened lazily on
+// first use; a device that's locked or has its companion app closed
+// surfaces that as an error from SignTx rather than at construction time.
+type HardwareBackend struct {
+	name string
+	hub  *usbwallet.Hub
+}
+
+// NewLedgerBackend returns a HardwareBackend that enumerates Ledger
+// devices over USB/HID.
+func NewLedgerBackend() (*HardwareBackend, error) {
+	hub, err := usbwallet.NewLedgerHub()
+	if err != nil {
+		return nil, fmt.Errorf("accounts: opening ledger hub: %w", err)
+	}
+	return &HardwareBackend{name: "ledger", hub: hub}, nil
+}
+
+// NewTrezorBackend returns a HardwareBackend that enumerates Trezor
+// devices over USB/HID.
+func NewTrezorBackend() (*HardwareBackend, error) {
+	hub, err := usbwallet.NewTrezorHubWithHID()
+	if err != nil {
+		return nil, fmt.Errorf("accounts: opening trezor hub: %w", err)
+	}
+	return &HardwareBackend{name: "trezor", hub: hub}, nil
+}
+
+func (b *HardwareBackend) Name() string { return b.name }
+
+func (b *HardwareBackend) Accounts() []common.Address {
+	var addrs []common.Address
+	for _, w := range b.hub.Wallets() {
+		for _, a := range w.Accounts() {
+			addrs = append(addrs, a.Address)
+		}
+	}
+	return addrs
+}
+
+func (b *HardwareBackend) Contains(addr common.Address) bool {
+	_, _, err := b.findWallet(addr)
+	return err == nil
+}
+
+// findWallet returns the wallet and account pair controlling addr,
+// opening the wallet (prompting a connected-but-locked device, if
+// needed) first.
+func (b *HardwareBackend) findWallet(addr common.Address) (gethaccounts.Wallet, gethaccounts.Account, error) {
+	for _, w := range b.hub.Wallets() {
+		for _, a := range w.Accounts() {
+			if a.Address != addr {
+				continue
+			}
+			if err := w.Open(""); err != nil && err != gethaccounts.ErrWalletAlreadyOpen {
+				return nil, gethaccounts.Account{}, fmt.Errorf("%s: opening wallet: %w", b.name, err)
+			}
+			return w, a, nil
+		}
+	}
+	return nil, gethaccounts.Account{}, fmt.Errorf("%s: account %s not found", b.name, addr.Hex())
+}
+
+func (b *HardwareBackend) SignTx(ctx context.Context, addr common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	w, acct, err := b.findWallet(addr)
+	if err != nil {
+		return nil, err
+	}
+	return w.SignTx(acct, tx, chainID)
+}
@@ -0,0 +1,61 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// KeystoreBackend signs with a local scrypt-encrypted JSON keystore on
+// disk, via go-ethereum's own accounts/keystore package rather than
+// reimplementing key derivation and encryption ourselves.
+type KeystoreBackend struct {
+	ks *keystore.KeyStore
+}
+
+// NewKeystoreBackend opens (creating if necessary) the keystore directory
+// at dir, using geth's standard scrypt parameters.
+func NewKeystoreBackend(dir string) *KeystoreBackend {
+	return &KeystoreBackend{
+		ks: keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP),
+	}
+}
+
+func (b *KeystoreBackend) Name() string { return "keystore" }
+
+func (b *KeystoreBackend) Accounts() []common.Address {
+	accts := b.ks.Accounts()
+	addrs := make([]common.Address, len(accts))
+	for i, a := range accts {
+		addrs[i] = a.Address
+	}
+	return addrs
+}
+
+func (b *KeystoreBackend) Contains(addr common.Address) bool {
+	return b.ks.HasAddress(addr)
+}
+
+// Unlock decrypts addr's key with passphrase and keeps it in memory for
+// subsequent SignTx calls until Lock is called.
+func (b *KeystoreBackend) Unlock(addr common.Address, passphrase string) error {
+	return b.ks.Unlock(gethaccounts.Account{Address: addr}, passphrase)
+}
+
+// Lock discards addr's decrypted key from memory.
+func (b *KeystoreBackend) Lock(addr common.Address) error {
+	return b.ks.Lock(addr)
+}
+
+func (b *KeystoreBackend) SignTx(ctx context.Context, addr common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	acct, err := b.ks.Find(gethaccounts.Account{Address: addr})
+	if err != nil {
+		return nil, fmt.Errorf("keystore: finding account %s: %w", addr.Hex(), err)
+	}
+	return b.ks.SignTx(acct, tx, chainID)
+}
@@ -0,0 +1,124 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/rpc"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+// Handshake is shared between the host and every plugin binary so a
+// stray executable launched by mistake can't be mistaken for an adapter
+// plugin. go-plugin negotiates this over the process's stdout before
+// handing off to the RPC transport.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "YFA_ADAPTER_PLUGIN",
+	MagicCookieValue: "yield-farming-aggregator-adapter-v1",
+}
+
+// PluginMap is shared between plugin.NewClient (host side, in
+// Registry.BuildOutOfProcess) and plugin.Serve (plugin binary side, via
+// Serve below) so both agree on the single "adapter" plugin type carried
+// over the connection.
+var PluginMap = map[string]goplugin.Plugin{
+	"adapter": &netRPCAdapterPlugin{},
+}
+
+// Serve runs impl as an adapter plugin binary: it blocks, handling
+// requests from the host process. Call this from a plugin binary's main().
+func Serve(impl Adapter) {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         map[string]goplugin.Plugin{"adapter": &netRPCAdapterPlugin{Impl: impl}},
+	})
+}
+
+// netRPCAdapterPlugin is the go-plugin glue exposing an Adapter across the
+// process boundary.
+//
+// Scope note: this runs over go-plugin's net/rpc protocol on a
+// unix-domain socket, not the gRPC transport originally requested for
+// this subsystem. Wiring up gRPC means implementing the GRPCServer/
+// GRPCClient side of goplugin.Plugin against generated protobuf types,
+// which needs a protoc/protoc-gen-go toolchain this build doesn't have
+// yet; net/rpc gets the same out-of-process isolation and the same
+// Adapter surface with plain net/rpc-codec'd Go types instead. Treat
+// GRPCServer/GRPCClient plus AllowedProtocols as the known follow-up once
+// that toolchain lands, not an equivalent already delivered here.
+type netRPCAdapterPlugin struct {
+	Impl Adapter
+}
+
+func (p *netRPCAdapterPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return &adapterRPCServer{impl: p.Impl}, nil
+}
+
+func (p *netRPCAdapterPlugin) Client(_ *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &adapterRPCClient{client: c}, nil
+}
+
+// adapterRPCServer runs inside the plugin binary, dispatching host calls
+// to the real Adapter implementation.
+type adapterRPCServer struct {
+	impl Adapter
+}
+
+func (s *adapterRPCServer) Key(_ struct{}, resp *string) error {
+	*resp = s.impl.Key()
+	return nil
+}
+
+func (s *adapterRPCServer) Topics(_ struct{}, resp *[]common.Hash) error {
+	*resp = s.impl.Topics()
+	return nil
+}
+
+func (s *adapterRPCServer) Decode(log types.Log, resp *Event) error {
+	event, err := s.impl.Decode(log)
+	if err != nil {
+		return err
+	}
+	*resp = event
+	return nil
+}
+
+// adapterRPCClient runs in the host process and satisfies Adapter by
+// calling out to the plugin binary over net/rpc.
+type adapterRPCClient struct {
+	client *rpc.Client
+}
+
+func (c *adapterRPCClient) Key() string {
+	var resp string
+	if err := c.client.Call("Plugin.Key", new(struct{}), &resp); err != nil {
+		return ""
+	}
+	return resp
+}
+
+func (c *adapterRPCClient) Topics() []common.Hash {
+	var resp []common.Hash
+	if err := c.client.Call("Plugin.Topics", new(struct{}), &resp); err != nil {
+		return nil
+	}
+	return resp
+}
+
+func (c *adapterRPCClient) Decode(log types.Log) (Event, error) {
+	var resp Event
+	err := c.client.Call("Plugin.Decode", log, &resp)
+	return resp, err
+}
+
+// Snapshot can't cross the plugin boundary: it needs a live
+// *ethclient.Client, and go-plugin's RPC transport only carries
+// serializable values. Out-of-process adapters decode events only.
+func (c *adapterRPCClient) Snapshot(ctx context.Context, client *ethclient.Client, blockNumber *big.Int) (Position, error) {
+	return Position{}, fmt.Errorf("adapter: out-of-process adapters do not support Snapshot")
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// topic identifies one of the WS subscription streams the "yfa"
+// namespace exposes.
+type topic int
+
+const (
+	topicNewDeposits topic = iota
+	topicNewWithdraws
+	topicPriceUpdates
+)
+
+// filter narrows a subscription to a chain/protocol/user, mirroring how
+// eth_subscribe filters logs by address/topics. An empty field matches
+// anything.
+type filter struct {
+	chain    string
+	protocol string
+	user     string
+}
+
+func (f filter) match(chain, protocol, user string) bool {
+	if f.chain != "" && !strings.EqualFold(f.chain, chain) {
+		return false
+	}
+	if f.protocol != "" && !strings.EqualFold(f.protocol, protocol) {
+		return false
+	}
+	if f.user != "" && user != "" && !strings.EqualFold(f.user, user) {
+		return false
+	}
+	return true
+}
+
+type subscriber struct {
+	filter   filter
+	notifier *rpc.Notifier
+	sub      *rpc.Subscription
+}
+
+// Hub fans published events out to every live subscriber whose filter
+// matches, one rpc.Notifier per open WS connection — the same per-client
+// notify model go-ethereum's own eth_subscribe uses for newHeads/logs.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[topic][]*subscriber
+}
+
+// NewHub returns an empty Hub ready to accept subscriptions.
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[topic][]*subscriber)}
+}
+
+// subscribe registers a new subscriber for t, returning the rpc.Subscription
+// the calling RPC method should hand back to the client. It must be
+// called from within an RPC method invoked over a connection that
+// supports notifications (i.e. a WS connection).
+func (h *Hub) subscribe(ctx context.Context, t topic, f filter) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	sub := &subscriber{filter: f, notifier: notifier, sub: rpcSub}
+
+	h.mu.Lock()
+	h.subscribers[t] = append(h.subscribers[t], sub)
+	h.mu.Unlock()
+
+	go func() {
+		<-rpcSub.Err()
+		h.remove(t, sub)
+	}()
+
+	return rpcSub, nil
+}
+
+func (h *Hub) remove(t topic, target *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := h.subscribers[t]
+	for i, s := range subs {
+		if s == target {
+			h.subscribers[t] = append(subs[:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// publish notifies every subscriber on t whose filter matches
+// chain/protocol/user with payload.
+func (h *Hub) publish(t topic, chain, protocol, user string, payload interface{}) {
+	h.mu.Lock()
+	subs := append([]*subscriber{}, h.subscribers[t]...)
+	h.mu.Unlock()
+
+	for _, s := range subs {
+		if !s.filter.match(chain, protocol, user) {
+			continue
+		}
+		_ = s.notifier.Notify(s.sub.ID, payload)
+	}
+}
+
+// PublishDeposit notifies newDeposits subscribers matching chain/protocol/user.
+func (h *Hub) PublishDeposit(chain, protocol, user string, payload interface{}) {
+	h.publish(topicNewDeposits, chain, protocol, user, payload)
+}
+
+// PublishWithdraw notifies newWithdraws subscribers matching chain/protocol/user.
+func (h *Hub) PublishWithdraw(chain, protocol, user string, payload interface{}) {
+	h.publish(topicNewWithdraws, chain, protocol, user, payload)
+}
+
+// PublishPriceUpdate notifies priceUpdates subscribers matching chain/protocol.
+func (h *Hub) PublishPriceUpdate(chain, protocol string, payload interface{}) {
+	h.publish(topicPriceUpdates, chain, protocol, "", payload)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/store"
+)
+
+// defaultPollInterval is how often the Poller checks the store for new
+// rows to fan out to Hub subscribers. The message_queue config (NATS)
+// is the intended transport for push-based delivery from the indexer;
+// until that's wired up, polling the store it already writes to is a
+// simple and correct way to drive newDeposits/newWithdraws/priceUpdates.
+const defaultPollInterval = 5 * time.Second
+
+// Poller watches the store for rows the indexer has written since it
+// last checked and republishes them on hub for any matching WS
+// subscriber.
+type Poller struct {
+	store        *store.Store
+	hub          *Hub
+	pollInterval time.Duration
+
+	lastEventID int64
+	lastPrice   map[store.VaultRef]string // vault ref -> last published price-per-share
+}
+
+// NewPoller builds a Poller over store that republishes new rows on hub.
+func NewPoller(s *store.Store, hub *Hub) *Poller {
+	return &Poller{
+		store:        s,
+		hub:          hub,
+		pollInterval: defaultPollInterval,
+		lastPrice:    make(map[store.VaultRef]string),
+	}
+}
+
+// Run polls until ctx is cancelled.
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.pollEvents(ctx)
+			p.pollPrices(ctx)
+		}
+	}
+}
+
+func (p *Poller) pollEvents(ctx context.Context) {
+	events, err := p.store.EventsSince(ctx, p.lastEventID)
+	if err != nil {
+		log.Printf("api: polling events: %v", err)
+		return
+	}
+	for _, e := range events {
+		p.lastEventID = e.ID
+		switch e.EventName {
+		case "Deposit":
+			p.hub.PublishDeposit(e.Chain, e.Protocol, e.Owner, e)
+		case "Withdraw":
+			p.hub.PublishWithdraw(e.Chain, e.Protocol, e.Owner, e)
+		}
+	}
+}
+
+func (p *Poller) pollPrices(ctx context.Context) {
+	vaults, err := p.store.ListVaults(ctx)
+	if err != nil {
+		log.Printf("api: polling vaults: %v", err)
+		return
+	}
+	for _, ref := range vaults {
+		snap, err := p.store.VaultState(ctx, ref.Chain, ref.VaultAddress)
+		if err != nil {
+			log.Printf("api: polling vault state for %s/%s: %v", ref.Chain, ref.VaultAddress, err)
+			continue
+		}
+		price := snap.PricePerShare.String()
+		if p.lastPrice[ref] == price {
+			continue
+		}
+		p.lastPrice[ref] = price
+		p.hub.PublishPriceUpdate(ref.Chain, ref.Protocol, snap)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/config"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NamespaceService pairs the namespace a service is registered under
+// (e.g. "yfa") with the receiver whose exported methods become its
+// JSON-RPC methods.
+type NamespaceService struct {
+	Namespace string
+	Service   interface{}
+}
+
+// Server boots the JSON-RPC/WS API described by config.ServerConfig. A
+// single *rpc.Server instance serves plain JSON-RPC over HTTP POST at
+// "/" and eth_subscribe-style WS subscriptions at "/ws", matching how
+// geth's own node package exposes its rpc.Server.
+type Server struct {
+	httpServer *http.Server
+	rpcServer  *rpc.Server
+}
+
+// NewServer registers every entry in services under its namespace and
+// wires the JSON-RPC HTTP and WS endpoints on cfg.ApiPort. Callers
+// decide which services to pass in, which is how the set of registered
+// namespaces stays configurable per deployment.
+func NewServer(cfg config.ServerConfig, services []NamespaceService) (*Server, error) {
+	rpcServer := rpc.NewServer()
+	for _, svc := range services {
+		if err := rpcServer.RegisterName(svc.Namespace, svc.Service); err != nil {
+			return nil, fmt.Errorf("api: registering namespace %s: %w", svc.Namespace, err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", rpcServer)
+	mux.Handle("/ws", rpcServer.WebsocketHandler([]string{"*"}))
+
+	return &Server{
+		rpcServer: rpcServer,
+		httpServer: &http.Server{
+			Addr:    ":" + cfg.ApiPort,
+			Handler: mux,
+		},
+	}, nil
+}
+
+// Start begins serving and blocks until the listener stops. After a
+// call to Shutdown this returns http.ErrServerClosed, which callers
+// should treat as a clean exit rather than a failure.
+func (s *Server) Start() error {
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown gracefully stops the HTTP/WS listener and the underlying
+// rpc.Server, which unwinds any open subscriptions.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	s.rpcServer.Stop()
+	return err
+}
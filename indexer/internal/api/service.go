@@ -0,0 +1,53 @@
+// Package api exposes the indexer's Postgres store over a geth-style
+// JSON-RPC server (namespaced methods such as yfa_getVaultState) plus a
+// WebSocket subscription endpoint mirroring eth_subscribe semantics for
+// newDeposits, newWithdraws, and priceUpdates streams.
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/store"
+)
+
+// Service implements the "yfa" namespace's query methods. go-ethereum's
+// rpc.Server derives the JSON-RPC method name from the exported method
+// name (lowercasing the first letter), so GetVaultState is callable as
+// yfa_getVaultState, GetUserPositions as yfa_getUserPositions, and so on.
+type Service struct {
+	store *store.Store
+}
+
+// NewService builds the yfa namespace's query service over s.
+func NewService(s *store.Store) *Service {
+	return &Service{store: s}
+}
+
+// GetVaultState returns the most recently recorded snapshot for
+// vaultAddress on chain.
+func (s *Service) GetVaultState(ctx context.Context, chain, vaultAddress string) (store.VaultSnapshot, error) {
+	return s.store.VaultState(ctx, chain, vaultAddress)
+}
+
+// GetUserPositions returns user's net share position in every vault
+// they've interacted with on chain.
+func (s *Service) GetUserPositions(ctx context.Context, chain, user string) ([]store.UserPosition, error) {
+	return s.store.UserPositions(ctx, chain, user)
+}
+
+// GetTVLHistory returns every snapshot for vaultAddress on chain
+// recorded between fromUnix and toUnix (inclusive, as Unix seconds).
+func (s *Service) GetTVLHistory(ctx context.Context, chain, vaultAddress string, fromUnix, toUnix int64) ([]store.VaultSnapshot, error) {
+	if toUnix < fromUnix {
+		return nil, fmt.Errorf("api: toUnix (%d) precedes fromUnix (%d)", toUnix, fromUnix)
+	}
+	return s.store.TVLHistory(ctx, chain, vaultAddress, time.Unix(fromUnix, 0), time.Unix(toUnix, 0))
+}
+
+// ListProtocols returns every protocol name the store has recorded
+// events or snapshots for.
+func (s *Service) ListProtocols(ctx context.Context) ([]string, error) {
+	return s.store.ListProtocols(ctx)
+}
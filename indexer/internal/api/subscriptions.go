@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SubscriptionService implements the "yfa" namespace's subscription
+// methods. go-ethereum's rpc.Server recognizes a method returning
+// (*rpc.Subscription, error) as subscribable; a client calls
+// yfa_subscribe("newDeposits", chain, protocol, user) the same way it
+// would call eth_subscribe("newHeads").
+type SubscriptionService struct {
+	hub *Hub
+}
+
+// NewSubscriptionService exposes hub's streams as yfa_subscribe topics.
+func NewSubscriptionService(hub *Hub) *SubscriptionService {
+	return &SubscriptionService{hub: hub}
+}
+
+// NewDeposits streams Deposit events, optionally filtered by chain,
+// protocol, and/or depositing user (pass "" to leave a filter open).
+func (s *SubscriptionService) NewDeposits(ctx context.Context, chain, protocol, user string) (*rpc.Subscription, error) {
+	return s.hub.subscribe(ctx, topicNewDeposits, filter{chain: chain, protocol: protocol, user: user})
+}
+
+// NewWithdraws streams Withdraw events, optionally filtered by chain,
+// protocol, and/or withdrawing user.
+func (s *SubscriptionService) NewWithdraws(ctx context.Context, chain, protocol, user string) (*rpc.Subscription, error) {
+	return s.hub.subscribe(ctx, topicNewWithdraws, filter{chain: chain, protocol: protocol, user: user})
+}
+
+// PriceUpdates streams vault price-per-share changes, optionally
+// filtered by chain and/or protocol.
+func (s *SubscriptionService) PriceUpdates(ctx context.Context, chain, protocol string) (*rpc.Subscription, error) {
+	return s.hub.subscribe(ctx, topicPriceUpdates, filter{chain: chain, protocol: protocol})
+}
@@ -0,0 +1,290 @@
+// Package backfill drives the one-time historical scan of a chain's
+// vault/adapter logs from config.ChainConfig.StartBlock up to the
+// confirmed tip. A Coordinator fans N workers out over a shared
+// block-range cursor, halving a claimed range on RPC range-limit errors.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/chain"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/config"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/store"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// defaultConcurrency is used when a ChainConfig doesn't set backfill_concurrency.
+	defaultConcurrency = 4
+
+	// defaultRangeSize is the initial FilterLogs block span a worker claims at a time.
+	defaultRangeSize = 2000
+
+	// minRangeSize is the floor shrinkRangeSize won't lower future claims
+	// below; scanRange stops splitting a failing range once it spans
+	// minRangeSize blocks or fewer, so a split can yield halves smaller
+	// than this.
+	minRangeSize = 25
+
+	// defaultReorgDepth mirrors indexer.defaultReorgDepth.
+	defaultReorgDepth = 64
+
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// DecodeFunc turns a matched log into a persistable VaultEvent. ok is
+// false for logs the caller has no decoding for, which the coordinator
+// omits from the committed chunk rather than failing it.
+type DecodeFunc func(types.Log) (store.VaultEvent, bool)
+
+// Coordinator backfills a single chain's configured addresses.
+type Coordinator struct {
+	chainCfg  config.ChainConfig
+	connector chain.IChainConnector
+	db        *store.Store
+	addresses []common.Address
+	topics    []common.Hash
+	decode    DecodeFunc
+
+	concurrency int
+	target      uint64
+
+	mu        sync.Mutex
+	rangeSize uint64
+}
+
+// NewCoordinator builds a Coordinator over addresses/topics (normally the
+// same union the live indexer.Engine filters for) that decodes matched
+// logs with decode and persists them through db.
+func NewCoordinator(chainCfg config.ChainConfig, connector chain.IChainConnector, db *store.Store, addresses []common.Address, topics []common.Hash, decode DecodeFunc) *Coordinator {
+	concurrency := chainCfg.BackfillConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	return &Coordinator{
+		chainCfg:    chainCfg,
+		connector:   connector,
+		db:          db,
+		addresses:   addresses,
+		topics:      topics,
+		decode:      decode,
+		concurrency: concurrency,
+		rangeSize:   defaultRangeSize,
+	}
+}
+
+// Run backfills from the least-progressed configured address's last
+// committed block (or chainCfg.StartBlock, if none has ever run) up to
+// the confirmed tip, blocking until caught up. targetHeight is
+// re-derived after every pass so a catch-up that outlasts the reorg
+// window doesn't leave a gap before the live engine takes over.
+func (c *Coordinator) Run(ctx context.Context) error {
+	if len(c.addresses) == 0 {
+		return nil
+	}
+
+	for {
+		target, err := c.targetHeight(ctx)
+		if err != nil {
+			return err
+		}
+		c.target = target
+
+		start, err := c.startHeight(ctx)
+		if err != nil {
+			return err
+		}
+
+		if start > target {
+			log.Printf("backfill[%s]: caught up (progress %d >= target %d)", c.chainCfg.Name, start, target+1)
+			lagBlocks.WithLabelValues(c.chainCfg.Name).Set(0)
+			return nil
+		}
+
+		log.Printf("backfill[%s]: backfilling blocks %d-%d across %d workers", c.chainCfg.Name, start, target, c.concurrency)
+		cursor := &sharedCursor{next: start, target: target}
+		tracker := newCompletionTracker(start)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, c.concurrency)
+		for i := 0; i < c.concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- c.runWorker(ctx, cursor, tracker)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+		log.Printf("backfill[%s]: caught up to block %d, re-checking tip before handing off", c.chainCfg.Name, target)
+	}
+}
+
+// targetHeight returns the current tip minus the chain's reorg depth.
+func (c *Coordinator) targetHeight(ctx context.Context) (uint64, error) {
+	latest, err := c.connector.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("backfill[%s]: reading latest block: %w", c.chainCfg.Name, err)
+	}
+	depth := c.chainCfg.ReorgDepth
+	if depth == 0 {
+		depth = defaultReorgDepth
+	}
+	if latest <= depth {
+		return 0, nil
+	}
+	return latest - depth, nil
+}
+
+// startHeight returns the lowest next-block-to-scan across every
+// configured address, so a protocol added later still gets its history
+// filled in. Re-scanning blocks already covered for other addresses is
+// harmless: InsertEvent is idempotent.
+func (c *Coordinator) startHeight(ctx context.Context) (uint64, error) {
+	start := c.chainCfg.StartBlock
+	for i, addr := range c.addresses {
+		committed, ok, err := c.db.HighestBackfilled(ctx, c.chainCfg.Name, addr.Hex())
+		if err != nil {
+			return 0, fmt.Errorf("backfill[%s]: reading progress for %s: %w", c.chainCfg.Name, addr.Hex(), err)
+		}
+		next := c.chainCfg.StartBlock
+		if ok {
+			next = committed + 1
+		}
+		if i == 0 || next < start {
+			start = next
+		}
+	}
+	return start, nil
+}
+
+func (c *Coordinator) runWorker(ctx context.Context, cursor *sharedCursor, tracker *completionTracker) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+		from, to, ok := cursor.claim(c.currentRangeSize())
+		if !ok {
+			return nil
+		}
+		if err := c.scanRange(ctx, from, to, tracker); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Coordinator) currentRangeSize() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rangeSize
+}
+
+// shrinkRangeSize halves the range future claims will request, given that
+// a range of failedSize just proved too large for the active endpoint.
+func (c *Coordinator) shrinkRangeSize(failedSize uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	half := failedSize / 2
+	if half < minRangeSize {
+		half = minRangeSize
+	}
+	if half < c.rangeSize {
+		c.rangeSize = half
+	}
+}
+
+// scanRange fetches and commits [from, to], splitting the range in half
+// and retrying each half on a range-limit error, or backing off
+// exponentially and retrying the same range on any other error.
+func (c *Coordinator) scanRange(ctx context.Context, from, to uint64, tracker *completionTracker) error {
+	backoff := initialBackoff
+	for {
+		logs, err := c.connector.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: c.addresses,
+			Topics:    [][]common.Hash{c.topics},
+		})
+		if err == nil {
+			return c.commitRange(ctx, from, to, logs, tracker)
+		}
+
+		if isRangeTooLargeError(err) && to > from && to-from+1 > minRangeSize {
+			c.shrinkRangeSize(to - from + 1)
+			mid := from + (to-from)/2
+			log.Printf("backfill[%s]: range %d-%d too large (%v), splitting at %d", c.chainCfg.Name, from, to, err, mid)
+			if err := c.scanRange(ctx, from, mid, tracker); err != nil {
+				return err
+			}
+			return c.scanRange(ctx, mid+1, to, tracker)
+		}
+
+		rangeErrorsTotal.WithLabelValues(c.chainCfg.Name, c.connector.ActiveHTTPEndpoint()).Inc()
+		log.Printf("backfill[%s]: scanning %d-%d: %v, retrying in %s", c.chainCfg.Name, from, to, err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// commitRange decodes and persists every log recognized by c.decode from
+// [from, to]. Progress is only advanced up to tracker's contiguous
+// frontier, not to: ranges can finish out of claim order, and moving
+// progress past a gap would let a restart skip the still-unscanned range
+// between them. The frontier advance and the database commit happen
+// under tracker's lock, so a worker whose commit fails can't leave
+// another worker to race ahead on a frontier that was never durably
+// persisted.
+func (c *Coordinator) commitRange(ctx context.Context, from, to uint64, logs []types.Log, tracker *completionTracker) error {
+	events := make([]store.VaultEvent, 0, len(logs))
+	for _, l := range logs {
+		if e, ok := c.decode(l); ok {
+			events = append(events, e)
+		}
+	}
+
+	addrs := make([]string, len(c.addresses))
+	for i, a := range c.addresses {
+		addrs[i] = a.Hex()
+	}
+
+	var frontier uint64
+	var advanced bool
+	err := tracker.complete(from, to, func(f uint64, a bool) error {
+		frontier, advanced = f, a
+		return c.db.CommitBackfillChunk(ctx, c.chainCfg.Name, addrs, events, f, a)
+	})
+	if err != nil {
+		return fmt.Errorf("backfill[%s]: committing chunk %d-%d: %w", c.chainCfg.Name, from, to, err)
+	}
+
+	blocksScanned.WithLabelValues(c.chainCfg.Name).Add(float64(to - from + 1))
+	if advanced {
+		if c.target > frontier {
+			lagBlocks.WithLabelValues(c.chainCfg.Name).Set(float64(c.target - frontier))
+		} else {
+			lagBlocks.WithLabelValues(c.chainCfg.Name).Set(0)
+		}
+	}
+	return nil
+}
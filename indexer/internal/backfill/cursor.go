@@ -0,0 +1,87 @@
+package backfill
+
+import "sync"
+
+// sharedCursor is the block range N backfill workers claim chunks from
+// concurrently. It hands out non-overlapping, contiguous ranges up to
+// target, in order, so together the claims cover every block with no
+// gaps; workers may still finish them out of order, which
+// completionTracker accounts for.
+type sharedCursor struct {
+	mu     sync.Mutex
+	next   uint64
+	target uint64
+}
+
+// claim reserves up to size blocks starting at the cursor's current
+// position and advances past them. ok is false once next has passed
+// target, meaning every block has been claimed by some worker.
+func (c *sharedCursor) claim(size uint64) (from, to uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.next > c.target {
+		return 0, 0, false
+	}
+	from = c.next
+	to = from + size - 1
+	if to > c.target {
+		to = c.target
+	}
+	c.next = to + 1
+	return from, to, true
+}
+
+// completionTracker reassembles the out-of-order completions of a
+// sharedCursor's claimed ranges back into a contiguous frontier, since
+// workers commit concurrently and a later-claimed range can finish
+// before an earlier one that's still retrying.
+type completionTracker struct {
+	mu      sync.Mutex
+	next    uint64
+	pending map[uint64]uint64
+}
+
+// newCompletionTracker starts the frontier at start, the height below
+// which everything is already known to be committed.
+func newCompletionTracker(start uint64) *completionTracker {
+	return &completionTracker{next: start, pending: make(map[uint64]uint64)}
+}
+
+// complete records [from, to] as scanned and invokes commit with the new
+// contiguous frontier, which only advances past from once next actually
+// reaches it; a range that finishes ahead of an earlier, still-incomplete
+// one is held in pending until that gap closes. The tracker's state is
+// only mutated if commit returns nil, and the lock is held for its
+// duration, so a concurrent completion can never observe (or build a
+// commit on top of) a frontier advance that didn't actually land.
+func (t *completionTracker) complete(from, to uint64, commit func(frontier uint64, advanced bool) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[from] = to
+
+	cursor := t.next
+	var frontier uint64
+	var advanced bool
+	var closedGaps []uint64
+	for {
+		end, ok := t.pending[cursor]
+		if !ok {
+			break
+		}
+		closedGaps = append(closedGaps, cursor)
+		cursor = end + 1
+		frontier = end
+		advanced = true
+	}
+
+	if err := commit(frontier, advanced); err != nil {
+		delete(t.pending, from)
+		return err
+	}
+
+	for _, gapStart := range closedGaps {
+		delete(t.pending, gapStart)
+	}
+	t.next = cursor
+	return nil
+}
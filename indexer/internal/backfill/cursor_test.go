@@ -0,0 +1,111 @@
+package backfill
+
+import "testing"
+
+func TestSharedCursorClaimPartitionsContiguously(t *testing.T) {
+	c := &sharedCursor{next: 100, target: 249}
+
+	from, to, ok := c.claim(50)
+	if !ok || from != 100 || to != 149 {
+		t.Fatalf("claim(50) = (%d, %d, %v), want (100, 149, true)", from, to, ok)
+	}
+
+	from, to, ok = c.claim(50)
+	if !ok || from != 150 || to != 199 {
+		t.Fatalf("second claim(50) = (%d, %d, %v), want (150, 199, true)", from, to, ok)
+	}
+}
+
+func TestSharedCursorClaimClampsToTarget(t *testing.T) {
+	c := &sharedCursor{next: 240, target: 249}
+
+	from, to, ok := c.claim(50)
+	if !ok || from != 240 || to != 249 {
+		t.Fatalf("claim(50) at the tail = (%d, %d, %v), want (240, 249, true)", from, to, ok)
+	}
+
+	if _, _, ok := c.claim(50); ok {
+		t.Fatalf("claim after target reached = ok, want exhausted (false)")
+	}
+}
+
+// recordCompletion is a test helper that drives complete and captures the
+// frontier/advanced it was called with, standing in for a real DB commit.
+func recordCompletion(t *testing.T, tr *completionTracker, from, to uint64) (frontier uint64, advanced bool) {
+	t.Helper()
+	if err := tr.complete(from, to, func(f uint64, a bool) error {
+		frontier, advanced = f, a
+		return nil
+	}); err != nil {
+		t.Fatalf("complete(%d, %d) returned error: %v", from, to, err)
+	}
+	return frontier, advanced
+}
+
+func TestCompletionTrackerHoldsOutOfOrderCompletion(t *testing.T) {
+	tr := newCompletionTracker(100)
+
+	// The second range finishes first; the frontier can't move past the
+	// still-unscanned first range yet.
+	if _, advanced := recordCompletion(t, tr, 150, 199); advanced {
+		t.Fatalf("complete(150, 199) advanced before (100, 149) completed")
+	}
+
+	frontier, advanced := recordCompletion(t, tr, 100, 149)
+	if !advanced || frontier != 199 {
+		t.Fatalf("complete(100, 149) = (%d, %v), want (199, true) once the gap closes", frontier, advanced)
+	}
+}
+
+func TestCompletionTrackerInOrderAdvancesImmediately(t *testing.T) {
+	tr := newCompletionTracker(0)
+
+	frontier, advanced := recordCompletion(t, tr, 0, 49)
+	if !advanced || frontier != 49 {
+		t.Fatalf("complete(0, 49) = (%d, %v), want (49, true)", frontier, advanced)
+	}
+
+	frontier, advanced = recordCompletion(t, tr, 50, 99)
+	if !advanced || frontier != 99 {
+		t.Fatalf("complete(50, 99) = (%d, %v), want (99, true)", frontier, advanced)
+	}
+}
+
+func TestCompletionTrackerDoesNotAdvanceOnCommitFailure(t *testing.T) {
+	tr := newCompletionTracker(0)
+
+	commitErr := errString("commit failed")
+	err := tr.complete(0, 49, func(uint64, bool) error { return commitErr })
+	if err != commitErr {
+		t.Fatalf("complete returned %v, want the commit error", err)
+	}
+
+	// The failed range isn't recorded as scanned, so a later, successful completion
+	// of the same range closes the gap rather than being told it's already done.
+	frontier, advanced := recordCompletion(t, tr, 0, 49)
+	if !advanced || frontier != 49 {
+		t.Fatalf("retry complete(0, 49) = (%d, %v), want (49, true)", frontier, advanced)
+	}
+}
+
+func TestIsRangeTooLargeError(t *testing.T) {
+	cases := []struct {
+		msg  string
+		want bool
+	}{
+		{"query returned more than 10000 results", true},
+		{"block range is too wide for this endpoint", true},
+		{"-32005", true},
+		{"connection refused", false},
+		{"execution reverted", false},
+	}
+	for _, tc := range cases {
+		if got := isRangeTooLargeError(errString(tc.msg)); got != tc.want {
+			t.Errorf("isRangeTooLargeError(%q) = %v, want %v", tc.msg, got, tc.want)
+		}
+	}
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
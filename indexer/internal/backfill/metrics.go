@@ -0,0 +1,24 @@
+package backfill
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	blocksScanned = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "yfa_backfill_blocks_scanned_total",
+		Help: "Blocks scanned by the historical backfill coordinator, per chain.",
+	}, []string{"chain"})
+
+	lagBlocks = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "yfa_backfill_lag_blocks",
+		Help: "Blocks remaining between the backfill cursor and its target height, per chain.",
+	}, []string{"chain"})
+
+	rangeErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "yfa_backfill_range_errors_total",
+		Help: "RPC errors encountered scanning a block range during backfill, per chain and endpoint.",
+	}, []string{"chain", "endpoint"})
+)
+
+func init() {
+	prometheus.MustRegister(blocksScanned, lagBlocks, rangeErrorsTotal)
+}
@@ -0,0 +1,36 @@
+package backfill
+
+import "strings"
+
+// rangeTooLargeSubstrings match the error text RPC providers return when a
+// FilterLogs query spans too many blocks or would return too many
+// results, as distinct from a transient connectivity failure. Wording
+// varies by provider (Alchemy, Infura, and plain geth nodes all phrase
+// this differently), so this matches on substrings rather than a single
+// known message.
+var rangeTooLargeSubstrings = []string{
+	"query returned more than",
+	"block range",
+	"range is too large",
+	"too many results",
+	"limit exceeded",
+	"response size exceeded",
+	"-32005", // eth_getLogs "query returned more than N results" error code
+}
+
+// isRangeTooLargeError reports whether err looks like the active endpoint
+// rejected the query for being too broad, as opposed to a transient RPC
+// failure that a plain retry (without shrinking the range) would recover
+// from.
+func isRangeTooLargeError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range rangeTooLargeSubstrings {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
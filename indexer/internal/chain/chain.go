@@ -1,26 +1,316 @@
 package chain
 
 import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
 	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-
+// IChainConnector is the subset of ChainConnector that downstream packages
+// (indexer, backfill, tracer, executor) depend on.
 type IChainConnector interface {
 	GetClient() *ethclient.Client
+	GetWSClient() *ethclient.Client
+	BlockNumber(ctx context.Context) (uint64, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	ActiveHTTPEndpoint() string
+	Close()
 }
 
+// ChainConnector wraps the HTTP and WS RPC endpoints configured for a
+// chain in a health-checked failover dispatcher: a call is retried
+// against the next healthy endpoint on a retryable error, and a
+// background prober re-promotes dead endpoints once they recover.
 type ChainConnector struct {
 	chainConfig config.ChainConfig
-	client *ethclient.Client
+
+	httpPool *endpointPool
+	wsPool   *endpointPool
+
+	probeInterval time.Duration
+	stopProbe     chan struct{}
+}
+
+// NewChainConnector dials every endpoint in chainConfig.RpcHttpEndpoints
+// (and, if present, RpcWsEndpoints) and starts the background health
+// prober. It fails only if no HTTP endpoint is configured or the primary
+// HTTP endpoint can't be dialed.
+func NewChainConnector(chainConfig config.ChainConfig) (*ChainConnector, error) {
+	if len(chainConfig.RpcHttpEndpoints) == 0 {
+		return nil, fmt.Errorf("chain %s: no rpc_http_endpoints configured", chainConfig.Name)
+	}
+
+	httpEndpoints, err := dialEndpoints(chainConfig.RpcHttpEndpoints, true)
+	if err != nil {
+		return nil, fmt.Errorf("chain %s: dialing primary http endpoint: %w", chainConfig.Name, err)
+	}
+
+	// WS is optional end to end, so a failed dial here must degrade
+	// rather than fail NewChainConnector.
+	wsEndpoints, _ := dialEndpoints(chainConfig.RpcWsEndpoints, false)
+
+	c := &ChainConnector{
+		chainConfig:   chainConfig,
+		httpPool:      newEndpointPool(httpEndpoints),
+		probeInterval: defaultProbeInterval,
+		stopProbe:     make(chan struct{}),
+	}
+	if len(wsEndpoints) > 0 {
+		c.wsPool = newEndpointPool(wsEndpoints)
+	}
+
+	go c.probeLoop()
+	return c, nil
 }
 
+// dialEndpoints eagerly dials every URL. One that fails to dial is kept
+// in the pool as unhealthy with a nil client, rather than dropped, so
+// the probe loop can redial and promote it later. If requirePrimary is
+// set and index 0 fails to dial, that error is returned instead.
+func dialEndpoints(urls []string, requirePrimary bool) ([]*endpoint, error) {
+	endpoints := make([]*endpoint, 0, len(urls))
+	for i, url := range urls {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			if i == 0 && requirePrimary {
+				return nil, err
+			}
+			endpoints = append(endpoints, &endpoint{url: url, client: nil, healthy: false})
+			continue
+		}
+		endpoints = append(endpoints, &endpoint{url: url, client: client, healthy: true})
+	}
+	return endpoints, nil
+}
+
+// probeLoop periodically re-probes dead endpoints in both pools and
+// promotes any that respond back to healthy.
+func (c *ChainConnector) probeLoop() {
+	ticker := time.NewTicker(c.probeInterval)
+	defer ticker.Stop()
+
+	probe := func(ctx context.Context, client *ethclient.Client) error {
+		_, err := client.BlockNumber(ctx)
+		return err
+	}
+
+	for {
+		select {
+		case <-c.stopProbe:
+			return
+		case <-ticker.C:
+			c.httpPool.probeDead(context.Background(), probe)
+			if c.wsPool != nil {
+				c.wsPool.probeDead(context.Background(), probe)
+			}
+		}
+	}
+}
+
+// call runs fn against the current active HTTP endpoint, failing over to
+// the next healthy one on a retryable error until either fn succeeds or
+// every endpoint has been exhausted.
+func (c *ChainConnector) call(ctx context.Context, fn func(*ethclient.Client) error) error {
+	var lastErr error
+	for {
+		ep := c.httpPool.active()
+		if ep == nil {
+			if lastErr != nil {
+				return fmt.Errorf("chain %s: all rpc endpoints unhealthy, last error: %w", c.chainConfig.Name, lastErr)
+			}
+			return fmt.Errorf("chain %s: no rpc endpoints configured", c.chainConfig.Name)
+		}
 
+		err := fn(ep.client)
+		if err == nil {
+			return nil
+		}
+		if !isRetryableRPCError(err) {
+			return err
+		}
 
-func NewChainConnector(chainConfig, client *ethclient.Client) *ChainConnector {
-	return &ChainConnector{
-		chainConfig: chainConfig,
-		client: client,
+		lastErr = err
+		c.httpPool.markUnhealthy(ep)
 	}
 }
 
+// GetClient returns the *ethclient.Client backing the active HTTP
+// endpoint. Prefer the wrapper methods below, which retry across
+// endpoints; GetClient is for callers that need the raw client.
+func (c *ChainConnector) GetClient() *ethclient.Client {
+	if ep := c.httpPool.active(); ep != nil {
+		return ep.client
+	}
+	return nil
+}
+
+// GetWSClient returns the *ethclient.Client backing the active WS
+// endpoint, or nil if no WS endpoints are configured or none are healthy.
+func (c *ChainConnector) GetWSClient() *ethclient.Client {
+	if c.wsPool == nil {
+		return nil
+	}
+	if ep := c.wsPool.active(); ep != nil {
+		return ep.client
+	}
+	return nil
+}
+
+func (c *ChainConnector) BlockNumber(ctx context.Context) (uint64, error) {
+	var result uint64
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		n, err := client.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		result = n
+		return nil
+	})
+	return result, err
+}
+
+func (c *ChainConnector) ChainID(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		id, err := client.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		result = id
+		return nil
+	})
+	return result, err
+}
+
+func (c *ChainConnector) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var result *types.Header
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		header, err := client.HeaderByNumber(ctx, number)
+		if err != nil {
+			return err
+		}
+		result = header
+		return nil
+	})
+	return result, err
+}
+
+// ActiveHTTPEndpoint returns the URL of the endpoint the httpPool would
+// currently dispatch to, or "" if none are healthy.
+func (c *ChainConnector) ActiveHTTPEndpoint() string {
+	if ep := c.httpPool.active(); ep != nil {
+		return ep.url
+	}
+	return ""
+}
+
+func (c *ChainConnector) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		logs, err := client.FilterLogs(ctx, query)
+		if err != nil {
+			return err
+		}
+		result = logs
+		return nil
+	})
+	return result, err
+}
+
+func (c *ChainConnector) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var result uint64
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		n, err := client.PendingNonceAt(ctx, account)
+		if err != nil {
+			return err
+		}
+		result = n
+		return nil
+	})
+	return result, err
+}
+
+func (c *ChainConnector) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		price, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return err
+		}
+		result = price
+		return nil
+	})
+	return result, err
+}
+
+func (c *ChainConnector) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		tip, err := client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return err
+		}
+		result = tip
+		return nil
+	})
+	return result, err
+}
+
+func (c *ChainConnector) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	var result uint64
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		gas, err := client.EstimateGas(ctx, msg)
+		if err != nil {
+			return err
+		}
+		result = gas
+		return nil
+	})
+	return result, err
+}
+
+// SendTransaction still fails over on a retryable error: a duplicate
+// broadcast is a no-op, but a caller that gets an ambiguous error should
+// confirm via TransactionReceipt rather than assume failure.
+func (c *ChainConnector) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.call(ctx, func(client *ethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
+}
+
+func (c *ChainConnector) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	var result *types.Receipt
+	err := c.call(ctx, func(client *ethclient.Client) error {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			return err
+		}
+		result = receipt
+		return nil
+	})
+	return result, err
+}
+
+// Close stops the health prober and closes every dialed client.
+func (c *ChainConnector) Close() {
+	close(c.stopProbe)
+	c.httpPool.close()
+	if c.wsPool != nil {
+		c.wsPool.close()
+	}
+}
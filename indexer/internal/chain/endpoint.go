@@ -0,0 +1,148 @@
+package chain
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// defaultProbeInterval is how often dead endpoints are re-probed with a
+// cheap eth_blockNumber call so they can be promoted back into rotation.
+const defaultProbeInterval = 30 * time.Second
+
+// defaultProbeTimeout bounds a single re-probe so a still-dead endpoint
+// can't stall the health-check goroutine.
+const defaultProbeTimeout = 5 * time.Second
+
+// endpoint tracks a single dialed RPC connection and whether the failover
+// dispatcher currently considers it usable.
+type endpoint struct {
+	url     string
+	client  *ethclient.Client
+	healthy bool
+}
+
+// endpointPool is a health-tracked, ordered set of RPC endpoints for a
+// single transport (HTTP or WS). Index 0 is the configured primary; the
+// pool always prefers the lowest-indexed healthy endpoint.
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+func newEndpointPool(endpoints []*endpoint) *endpointPool {
+	return &endpointPool{endpoints: endpoints}
+}
+
+// active returns the lowest-indexed healthy endpoint, or nil if every
+// endpoint in the pool is currently marked unhealthy.
+func (p *endpointPool) active() *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, ep := range p.endpoints {
+		if ep.healthy {
+			return ep
+		}
+	}
+	return nil
+}
+
+// markUnhealthy demotes ep so subsequent calls skip it until a probe
+// promotes it back.
+func (p *endpointPool) markUnhealthy(ep *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.healthy = false
+}
+
+// probeDead re-probes every unhealthy endpoint with a cheap call and
+// promotes the ones that respond back to healthy. An endpoint that never
+// dialed successfully (client == nil) is redialed first; if the redial
+// itself fails it's left unhealthy for the next tick.
+func (p *endpointPool) probeDead(ctx context.Context, probe func(context.Context, *ethclient.Client) error) {
+	p.mu.Lock()
+	dead := make([]*endpoint, 0)
+	for _, ep := range p.endpoints {
+		if !ep.healthy {
+			dead = append(dead, ep)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, ep := range dead {
+		p.mu.Lock()
+		client := ep.client
+		p.mu.Unlock()
+
+		if client == nil {
+			var err error
+			client, err = ethclient.Dial(ep.url)
+			if err != nil {
+				continue
+			}
+			p.mu.Lock()
+			ep.client = client
+			p.mu.Unlock()
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, defaultProbeTimeout)
+		err := probe(probeCtx, client)
+		cancel()
+		if err == nil {
+			p.mu.Lock()
+			ep.healthy = true
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *endpointPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, ep := range p.endpoints {
+		if ep.client != nil {
+			ep.client.Close()
+		}
+	}
+}
+
+// retryableSubstrings match error text produced by the classes of RPC
+// failure we expect a fallback endpoint to recover from: dropped
+// connections, timeouts, rate limiting, upstream 5xx, and the go-ethereum
+// "internal error" / "missing trie node" responses returned by pruned or
+// overloaded nodes.
+var retryableSubstrings = []string{
+	"connection refused",
+	"connection reset",
+	"context deadline exceeded",
+	"no such host",
+	"eof",
+	"429",
+	"too many requests",
+	"502",
+	"503",
+	"504",
+	"-32603",
+	"missing trie node",
+	"request timed out",
+}
+
+// isRetryableRPCError reports whether err looks like a transient failure
+// of the endpoint that served the call, as opposed to a permanent error
+// (bad request, revert, etc.) that would fail identically on every
+// endpoint.
+func isRetryableRPCError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range retryableSubstrings {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,10 @@ type Config struct {
 
 type ServerConfig struct {
 	ApiPort string `yaml:"api_port" mapstructure:"api_port"`
+
+	// Namespaces is the set of JSON-RPC namespaces to register (e.g. "yfa").
+	// Empty registers every namespace the binary knows about.
+	Namespaces []string `yaml:"namespaces" mapstructure:"namespaces"`
 }
 
 type DatabaseConfig struct {
@@ -27,14 +31,55 @@ type MessageQConfig struct {
 }
 
 type ChainConfig struct {
-	Name            string           `yaml:"name" mapstructure:"name"`
-	Key             string           `yaml:"key" mapstructure:"key"`
-	ChainID         int              `yaml:"chain_id" mapstructure:"chain_id"`
-	Enabled         bool             `yaml:"enabled" mapstructure:"enabled"`
-	RpcWsEndpoint   string           `yaml:"rpc_ws_endpoint" mapstructure:"rpc_ws_endpoint"`
-	RpcHttpEndpoint string           `yaml:"rpc_http_endpoint" mapstructure:"rpc_http_endpoint"`
-	StartBlock      uint64           `yaml:"start_block" mapstructure:"start_block"`
-	Protocols       []ProtocolConfig `yaml:"protocols" mapstructure:"protocols"`
+	Name string `yaml:"name" mapstructure:"name"`
+	Key  string `yaml:"key" mapstructure:"key"`
+
+	ChainID int  `yaml:"chain_id" mapstructure:"chain_id"`
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// RpcHttpEndpoints/RpcWsEndpoints are ordered primary-first; the
+	// connector falls back to the next entry on a retryable RPC error.
+	RpcHttpEndpoints []string `yaml:"rpc_http_endpoints" mapstructure:"rpc_http_endpoints"`
+	RpcWsEndpoints   []string `yaml:"rpc_ws_endpoints" mapstructure:"rpc_ws_endpoints"`
+
+	ReorgDepth    uint64 `yaml:"reorg_depth" mapstructure:"reorg_depth"`
+	Confirmations uint64 `yaml:"confirmations" mapstructure:"confirmations"`
+
+	// TracingEnabled gates the debug_traceTransaction enrichment in
+	// internal/tracer, since many public RPCs disable that namespace.
+	TracingEnabled bool `yaml:"tracing_enabled" mapstructure:"tracing_enabled"`
+
+	StartBlock uint64           `yaml:"start_block" mapstructure:"start_block"`
+	Protocols  []ProtocolConfig `yaml:"protocols" mapstructure:"protocols"`
+
+	// BackfillConcurrency defaults to backfill.defaultConcurrency when unset.
+	BackfillConcurrency int `yaml:"backfill_concurrency" mapstructure:"backfill_concurrency"`
+
+	// Signer enables the executor to sign and submit transactions on this
+	// chain. Omit it to run the chain read-only, which is the default.
+	Signer *SignerConfig `yaml:"signer" mapstructure:"signer"`
+}
+
+// SignerConfig selects and configures the accounts.Backend the executor
+// signs with for a single chain, plus the gas strategy it submits under.
+type SignerConfig struct {
+	SignerAddress string `yaml:"signer_address" mapstructure:"signer_address"`
+
+	// KeystorePath, if set, backs SignerAddress with a local
+	// scrypt-encrypted JSON keystore; PassphraseFile unlocks it on startup.
+	KeystorePath   string `yaml:"keystore_path" mapstructure:"keystore_path"`
+	PassphraseFile string `yaml:"passphrase_file" mapstructure:"passphrase_file"`
+
+	// ExternalSignerURL, if set instead, backs SignerAddress with a
+	// Clef-style external signer; PassphraseFile is unused in this mode.
+	ExternalSignerURL string `yaml:"external_signer_url" mapstructure:"external_signer_url"`
+
+	// HardwareWallet, if set instead, backs SignerAddress with a USB
+	// hardware wallet. One of "ledger" or "trezor".
+	HardwareWallet string `yaml:"hardware_wallet" mapstructure:"hardware_wallet"`
+
+	// GasStrategy is "legacy" or "eip1559". Defaults to "legacy".
+	GasStrategy string `yaml:"gas_strategy" mapstructure:"gas_strategy"`
 }
 
 type ProtocolConfig struct {
@@ -47,6 +92,11 @@ type AdapterConfig struct {
 	Name    string `yaml:"name" mapstructure:"name"`
 	Address string `yaml:"address" mapstructure:"address"`
 	AbiPath string `yaml:"abi_path" mapstructure:"abi_path"`
+
+	// PluginPath, if set, loads this adapter out of process from the
+	// go-plugin binary at this path (adapter.Registry.BuildOutOfProcess)
+	// instead of from the in-process registry keyed by Name.
+	PluginPath string `yaml:"plugin_path" mapstructure:"plugin_path"`
 }
 
 // LoadConfig function remains the same
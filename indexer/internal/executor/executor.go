@@ -0,0 +1,261 @@
+// Package executor turns decisions about what a vault should do next
+// into signed, confirmed on-chain transactions.
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/accounts"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/chain"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Decision describes a single on-chain action to take against a
+// configured vault, e.g. {Method: "harvest"} or {Method: "rebalance",
+// Args: [adapterA, adapterB]}.
+type Decision struct {
+	Chain        string
+	VaultAddress common.Address
+	Method       string
+	Args         []interface{}
+	Reason       string
+}
+
+// replaceTimeout is how long Submit waits for a transaction to be mined
+// before bumping gas by replaceBumpPercent and resubmitting, up to
+// maxReplacements times.
+const (
+	replaceTimeout      = 90 * time.Second
+	receiptPollInterval = 5 * time.Second
+	replaceBumpPercent  = 15
+	maxReplacements     = 5
+)
+
+// Executor signs and submits Decisions from a single configured signer
+// address, managing its own nonce sequence.
+type Executor struct {
+	chainCfg    config.ChainConfig
+	connector   chain.IChainConnector
+	accountsMgr *accounts.Manager
+	signer      common.Address
+	contractABI abi.ABI
+	gas         accounts.GasStrategy
+
+	nonceMu   sync.Mutex
+	nextNonce uint64
+	nonceSet  bool
+	released  []uint64
+}
+
+// NewExecutor builds an Executor that signs as signer (which must be
+// held by one of accountsMgr's backends) and packs Decision.Method/Args
+// against contractABI.
+func NewExecutor(chainCfg config.ChainConfig, connector chain.IChainConnector, accountsMgr *accounts.Manager, signer common.Address, contractABI abi.ABI, gas accounts.GasStrategy) *Executor {
+	return &Executor{
+		chainCfg:    chainCfg,
+		connector:   connector,
+		accountsMgr: accountsMgr,
+		signer:      signer,
+		contractABI: contractABI,
+		gas:         gas,
+	}
+}
+
+// Submit packs, signs, sends, and confirms d, bumping gas and
+// resubmitting under the same nonce if it isn't mined within
+// replaceTimeout. It returns the hash of whichever attempt was finally
+// mined.
+func (e *Executor) Submit(ctx context.Context, d Decision) (common.Hash, error) {
+	data, err := e.contractABI.Pack(d.Method, d.Args...)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("executor: packing %s: %w", d.Method, err)
+	}
+
+	nonce, err := e.reserveNonce(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	// Give the nonce back on any early return below; it's only consumed
+	// once SendTransaction actually broadcasts it.
+	release := true
+	defer func() {
+		if release {
+			e.releaseNonce(nonce)
+		}
+	}()
+
+	gasLimit, err := e.connector.EstimateGas(ctx, ethereum.CallMsg{
+		From: e.signer, To: &d.VaultAddress, Data: data,
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("executor: estimating gas for %s: %w", d.Method, err)
+	}
+
+	chainID, err := e.connector.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("executor: reading chain id: %w", err)
+	}
+
+	log.Printf("[%s] executor: submitting %s on %s (nonce=%d): %s", e.chainCfg.Name, d.Method, d.VaultAddress.Hex(), nonce, d.Reason)
+
+	for attempt := 0; ; attempt++ {
+		gasParams, err := e.gas.Suggest(ctx, e.connector)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("executor: pricing %s: %w", d.Method, err)
+		}
+		bumpGasParams(&gasParams, attempt)
+
+		tx := e.buildTx(nonce, d.VaultAddress, data, gasLimit, gasParams, chainID)
+		signed, err := e.accountsMgr.SignTx(ctx, e.signer, tx, chainID)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("executor: signing %s: %w", d.Method, err)
+		}
+
+		if err := e.connector.SendTransaction(ctx, signed); err != nil {
+			return common.Hash{}, fmt.Errorf("executor: sending %s: %w", d.Method, err)
+		}
+		// The chain has seen this nonce now; a later failure must replace
+		// it, not free it.
+		release = false
+
+		receipt, err := e.waitForReceipt(ctx, signed.Hash(), replaceTimeout)
+		if err == nil {
+			if receipt.Status == types.ReceiptStatusFailed {
+				return receipt.TxHash, fmt.Errorf("executor: %s Tx=%s reverted", d.Method, receipt.TxHash.Hex())
+			}
+			return receipt.TxHash, nil
+		}
+		if !errors.Is(err, errReceiptTimeout) {
+			return common.Hash{}, err
+		}
+		if attempt >= maxReplacements {
+			return common.Hash{}, fmt.Errorf("executor: %s Tx=%s not mined after %d replacements", d.Method, signed.Hash().Hex(), maxReplacements)
+		}
+		log.Printf("[%s] executor: %s Tx=%s not mined within %s, replacing with higher gas (attempt %d)",
+			e.chainCfg.Name, d.Method, signed.Hash().Hex(), replaceTimeout, attempt+2)
+	}
+}
+
+// buildTx constructs a legacy or EIP-1559 transaction depending on which
+// fields gasParams set.
+func (e *Executor) buildTx(nonce uint64, to common.Address, data []byte, gasLimit uint64, gasParams accounts.GasParams, chainID *big.Int) *types.Transaction {
+	if gasParams.GasTipCap != nil {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			To:        &to,
+			Data:      data,
+			Gas:       gasLimit,
+			GasTipCap: gasParams.GasTipCap,
+			GasFeeCap: gasParams.GasFeeCap,
+		})
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Data:     data,
+		Gas:      gasLimit,
+		GasPrice: gasParams.GasPrice,
+	})
+}
+
+// bumpGasParams raises gasParams by replaceBumpPercent for each prior
+// attempt, since a same-nonce resubmission must strictly increase price
+// to replace the still-pending original.
+func bumpGasParams(gasParams *accounts.GasParams, attempt int) {
+	if attempt == 0 {
+		return
+	}
+	bump := func(v *big.Int) *big.Int {
+		if v == nil {
+			return nil
+		}
+		factor := big.NewInt(int64(100 + replaceBumpPercent*attempt))
+		return new(big.Int).Div(new(big.Int).Mul(v, factor), big.NewInt(100))
+	}
+	gasParams.GasPrice = bump(gasParams.GasPrice)
+	gasParams.GasTipCap = bump(gasParams.GasTipCap)
+	gasParams.GasFeeCap = bump(gasParams.GasFeeCap)
+}
+
+// errReceiptTimeout distinguishes waitForReceipt giving up from a real
+// RPC failure, so Submit knows to replace rather than give up.
+var errReceiptTimeout = errors.New("executor: timed out waiting for receipt")
+
+// waitForReceipt polls for tx's receipt until it's mined or timeout
+// elapses, returning errReceiptTimeout in the latter case.
+func (e *Executor) waitForReceipt(ctx context.Context, txHash common.Hash, timeout time.Duration) (*types.Receipt, error) {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := e.connector.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("executor: checking receipt for %s: %w", txHash.Hex(), err)
+		}
+		if time.Now().After(deadline) {
+			return nil, errReceiptTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// reserveNonce returns the next nonce to use, seeding from
+// PendingNonceAt on first use. A nonce freed by releaseNonce is handed
+// out again before nextNonce advances.
+func (e *Executor) reserveNonce(ctx context.Context) (uint64, error) {
+	e.nonceMu.Lock()
+	defer e.nonceMu.Unlock()
+
+	if !e.nonceSet {
+		n, err := e.connector.PendingNonceAt(ctx, e.signer)
+		if err != nil {
+			return 0, fmt.Errorf("executor: reading pending nonce for %s: %w", e.signer.Hex(), err)
+		}
+		e.nextNonce = n
+		e.nonceSet = true
+	}
+
+	if len(e.released) > 0 {
+		minIdx := 0
+		for i, n := range e.released {
+			if n < e.released[minIdx] {
+				minIdx = i
+			}
+		}
+		nonce := e.released[minIdx]
+		e.released = append(e.released[:minIdx], e.released[minIdx+1:]...)
+		return nonce, nil
+	}
+
+	nonce := e.nextNonce
+	e.nextNonce++
+	return nonce, nil
+}
+
+// releaseNonce returns a nonce reserved by reserveNonce but never
+// successfully broadcast, so a later Submit can reuse it instead of the
+// sequence gapping forever.
+func (e *Executor) releaseNonce(nonce uint64) {
+	e.nonceMu.Lock()
+	defer e.nonceMu.Unlock()
+	e.released = append(e.released, nonce)
+}
@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReserveNonceSequential(t *testing.T) {
+	e := &Executor{nonceSet: true, nextNonce: 10}
+	for i, want := range []uint64{10, 11, 12} {
+		got, err := e.reserveNonce(context.Background())
+		if err != nil {
+			t.Fatalf("reserveNonce #%d: %v", i, err)
+		}
+		if got != want {
+			t.Fatalf("reserveNonce #%d = %d, want %d", i, got, want)
+		}
+	}
+}
+
+// TestReserveNonceReusesReleased covers the bug this fix addresses: a
+// nonce given back by releaseNonce must be handed out again before
+// nextNonce advances, so a failed Submit doesn't leave a permanent gap.
+func TestReserveNonceReusesReleased(t *testing.T) {
+	e := &Executor{nonceSet: true, nextNonce: 5}
+
+	n, err := e.reserveNonce(context.Background())
+	if err != nil || n != 5 {
+		t.Fatalf("reserveNonce = %d, %v, want 5, nil", n, err)
+	}
+	e.releaseNonce(n)
+
+	again, err := e.reserveNonce(context.Background())
+	if err != nil {
+		t.Fatalf("reserveNonce: %v", err)
+	}
+	if again != 5 {
+		t.Fatalf("reserveNonce after release = %d, want 5 (reused, not advanced past)", again)
+	}
+	if e.nextNonce != 6 {
+		t.Fatalf("nextNonce = %d, want 6", e.nextNonce)
+	}
+}
+
+func TestReserveNonceReusesLowestReleasedFirst(t *testing.T) {
+	e := &Executor{nonceSet: true, nextNonce: 0}
+
+	var reserved []uint64
+	for i := 0; i < 3; i++ {
+		n, err := e.reserveNonce(context.Background())
+		if err != nil {
+			t.Fatalf("reserveNonce #%d: %v", i, err)
+		}
+		reserved = append(reserved, n)
+	}
+
+	// Release out of order; the lowest released nonce should still come
+	// back first so the sequence fills in gap-first rather than LIFO.
+	e.releaseNonce(reserved[2])
+	e.releaseNonce(reserved[1])
+
+	got, err := e.reserveNonce(context.Background())
+	if err != nil {
+		t.Fatalf("reserveNonce: %v", err)
+	}
+	if got != reserved[1] {
+		t.Fatalf("reserveNonce = %d, want lowest released nonce %d", got, reserved[1])
+	}
+}
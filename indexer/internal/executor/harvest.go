@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/store"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultHarvestPollInterval is how often HarvestScanner checks vaults
+// for staleness.
+const defaultHarvestPollInterval = 10 * time.Minute
+
+// HarvestScanner emits a "harvest" Decision for every vault on chain
+// whose most recent FeesCollected event is older than staleThreshold (or
+// has never been recorded at all), the concrete rule the backlog request
+// gives as the motivating example for this package.
+type HarvestScanner struct {
+	store          *store.Store
+	chain          string
+	staleThreshold time.Duration
+	pollInterval   time.Duration
+}
+
+// NewHarvestScanner builds a HarvestScanner over db for chain's vaults,
+// flagging any whose FeesCollected history is older than staleThreshold.
+func NewHarvestScanner(db *store.Store, chain string, staleThreshold time.Duration) *HarvestScanner {
+	return &HarvestScanner{
+		store:          db,
+		chain:          chain,
+		staleThreshold: staleThreshold,
+		pollInterval:   defaultHarvestPollInterval,
+	}
+}
+
+// Run scans on h.pollInterval until ctx is cancelled, sending a Decision
+// to decisions for every vault it finds overdue.
+func (h *HarvestScanner) Run(ctx context.Context, decisions chan<- Decision) {
+	h.scan(ctx, decisions)
+
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.scan(ctx, decisions)
+		}
+	}
+}
+
+func (h *HarvestScanner) scan(ctx context.Context, decisions chan<- Decision) {
+	vaults, err := h.store.ListVaults(ctx)
+	if err != nil {
+		log.Printf("harvest scanner[%s]: listing vaults: %v", h.chain, err)
+		return
+	}
+
+	for _, v := range vaults {
+		if v.Chain != h.chain {
+			continue
+		}
+
+		last, ok, err := h.store.LastEvent(ctx, v.Chain, v.VaultAddress, "FeesCollected")
+		if err != nil {
+			log.Printf("harvest scanner[%s]: checking %s: %v", h.chain, v.VaultAddress, err)
+			continue
+		}
+		if ok && time.Since(last.RecordedAt) < h.staleThreshold {
+			continue
+		}
+
+		reason := "no FeesCollected event has ever been recorded"
+		if ok {
+			reason = fmt.Sprintf("last FeesCollected was %s ago", time.Since(last.RecordedAt).Round(time.Second))
+		}
+
+		decision := Decision{
+			Chain:        v.Chain,
+			VaultAddress: common.HexToAddress(v.VaultAddress),
+			Method:       "harvest",
+			Reason:       reason,
+		}
+		select {
+		case decisions <- decision:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
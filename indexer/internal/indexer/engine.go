@@ -0,0 +1,281 @@
+// Package indexer drives a single chain's event stream from its
+// subscribed head, replacing a naive "poll the last N blocks" loop with
+// reorg-aware, confirmation-gated log delivery.
+package indexer
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/chain"
+	"github.com/MichaelGenchev/YieldFarmingAggregator/indexer/internal/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// defaultReorgDepth is used when a ChainConfig doesn't set reorg_depth.
+const defaultReorgDepth = 64
+
+// httpPollInterval is how often the engine polls for a new head while
+// running in HTTP fallback mode.
+const httpPollInterval = 3 * time.Second
+
+// emittedKey identifies a single log for dedup and revert tracking.
+type emittedKey struct {
+	blockNumber uint64
+	txHash      common.Hash
+	logIndex    uint
+}
+
+// Engine consumes new heads for one chain (WS subscription, falling back
+// to HTTP polling), waits for Confirmations descendants before emitting
+// a matched log, and unwinds reorgs against the buffered tip.
+type Engine struct {
+	chainCfg  config.ChainConfig
+	connector chain.IChainConnector
+	addresses []common.Address
+	topics    []common.Hash
+
+	confirmations uint64
+	ring          *headerRing
+	emitted       map[emittedKey]common.Hash
+
+	out chan Event
+}
+
+// NewEngine builds an Engine that filters for topics across addresses on
+// the chain described by chainCfg. addresses/topics are normally the
+// union of every configured protocol's VaultAddress and adapter
+// addresses/signatures for that chain.
+func NewEngine(chainCfg config.ChainConfig, connector chain.IChainConnector, addresses []common.Address, topics []common.Hash) *Engine {
+	depth := chainCfg.ReorgDepth
+	if depth == 0 {
+		depth = defaultReorgDepth
+	}
+	return &Engine{
+		chainCfg:      chainCfg,
+		connector:     connector,
+		addresses:     addresses,
+		topics:        topics,
+		confirmations: chainCfg.Confirmations,
+		ring:          newHeaderRing(depth),
+		emitted:       make(map[emittedKey]common.Hash),
+		out:           make(chan Event, 256),
+	}
+}
+
+// Events returns the channel of confirmed (and, on reorg, reverted) log
+// events. It's closed when Run returns.
+func (e *Engine) Events() <-chan Event {
+	return e.out
+}
+
+// Run drives the engine until ctx is cancelled. It prefers a WS
+// subscription and transparently falls back to HTTP polling whenever the
+// subscription can't be established or drops.
+func (e *Engine) Run(ctx context.Context) error {
+	defer close(e.out)
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		wsClient := e.connector.GetWSClient()
+		if wsClient == nil {
+			if err := e.pollUntilWSAvailable(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		heads := make(chan *types.Header)
+		sub, err := wsClient.SubscribeNewHead(ctx, heads)
+		if err != nil {
+			log.Printf("indexer[%s]: ws subscription unavailable (%v), falling back to http polling", e.chainCfg.Name, err)
+			if err := e.pollUntilWSAvailable(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := e.runSubscribed(ctx, sub, heads); err != nil {
+			return err
+		}
+		// runSubscribed only returns nil after the subscription dropped;
+		// loop back around to fall back to polling / re-subscribe.
+	}
+}
+
+// runSubscribed pumps heads from an active WS subscription until it
+// drops or ctx is cancelled.
+func (e *Engine) runSubscribed(ctx context.Context, sub ethereum.Subscription, heads <-chan *types.Header) error {
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			log.Printf("indexer[%s]: ws subscription dropped (%v), falling back to http polling", e.chainCfg.Name, err)
+			return nil
+		case header := <-heads:
+			e.onNewHead(ctx, header)
+		}
+	}
+}
+
+// pollUntilWSAvailable polls for new heads over HTTP, checking on every
+// tick whether the WS endpoint has come back so Run can hop back onto
+// push-based delivery.
+func (e *Engine) pollUntilWSAvailable(ctx context.Context) error {
+	ticker := time.NewTicker(httpPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			latest, err := e.connector.BlockNumber(ctx)
+			if err != nil {
+				log.Printf("indexer[%s]: polling block number: %v", e.chainCfg.Name, err)
+				continue
+			}
+			header, err := e.connector.HeaderByNumber(ctx, new(big.Int).SetUint64(latest))
+			if err != nil {
+				log.Printf("indexer[%s]: fetching header %d: %v", e.chainCfg.Name, latest, err)
+				continue
+			}
+			e.onNewHead(ctx, header)
+
+			if e.connector.GetWSClient() != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// onNewHead reconciles header against the buffered tip (unwinding a
+// reorg first if the parent hash doesn't match), then scans for logs
+// that have just reached the required confirmation depth.
+func (e *Engine) onNewHead(ctx context.Context, header *types.Header) {
+	if tip := e.ring.tip(); tip != nil {
+		if header.Hash() == tip.Hash() {
+			return // duplicate head notification
+		}
+		if header.ParentHash != tip.Hash() {
+			e.handleReorg(ctx, header)
+		}
+	}
+	e.ring.push(header)
+	e.scanForConfirmedLogs(ctx, header)
+}
+
+// handleReorg walks backward from the previous tip to find the fork
+// point, invalidates logs in the abandoned range, then re-scans it
+// against the now-canonical chain.
+func (e *Engine) handleReorg(ctx context.Context, newHead *types.Header) {
+	forkPoint := newHead.Number.Uint64()
+	for forkPoint > 0 {
+		height := forkPoint - 1
+		buffered := e.ring.at(height)
+		if buffered == nil {
+			break // beyond our buffered window; nothing more to reconcile
+		}
+		canonical, err := e.connector.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+		if err != nil {
+			log.Printf("indexer[%s]: reorg: refetching header %d: %v", e.chainCfg.Name, height, err)
+			break
+		}
+		if canonical.Hash() == buffered.Hash() {
+			break
+		}
+		e.invalidateBlock(height, canonical.Hash())
+		forkPoint = height
+	}
+	e.ring.truncateFrom(forkPoint)
+
+	for height := forkPoint; height < newHead.Number.Uint64(); height++ {
+		canonical, err := e.connector.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+		if err != nil {
+			log.Printf("indexer[%s]: reorg: re-fetching canonical header %d: %v", e.chainCfg.Name, height, err)
+			continue
+		}
+		e.ring.push(canonical)
+		e.scanForConfirmedLogs(ctx, canonical)
+	}
+}
+
+// invalidateBlock emits a Reverted event for every previously-emitted log
+// at height whose recorded block hash no longer matches the canonical
+// chain, and forgets it so a re-scan can re-emit it under its new hash.
+func (e *Engine) invalidateBlock(height uint64, canonicalHash common.Hash) {
+	for key, blockHash := range e.emitted {
+		if key.blockNumber != height || blockHash == canonicalHash {
+			continue
+		}
+		e.out <- Event{
+			Log: types.Log{
+				BlockNumber: height,
+				BlockHash:   blockHash,
+				TxHash:      key.txHash,
+				Index:       key.logIndex,
+			},
+			Reverted: true,
+		}
+		delete(e.emitted, key)
+	}
+}
+
+// scanForConfirmedLogs filters for matched logs across the buffered
+// window up to head - Confirmations and emits any not already emitted.
+func (e *Engine) scanForConfirmedLogs(ctx context.Context, head *types.Header) {
+	headNum := head.Number.Uint64()
+	if headNum <= e.confirmations {
+		return
+	}
+	confirmedUpTo := headNum - e.confirmations
+
+	from := uint64(0)
+	if headNum > e.ring.depth {
+		from = headNum - e.ring.depth
+	}
+	if from > confirmedUpTo {
+		return
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(confirmedUpTo),
+		Addresses: e.addresses,
+		Topics:    [][]common.Hash{e.topics},
+	}
+	logs, err := e.connector.FilterLogs(ctx, query)
+	if err != nil {
+		log.Printf("indexer[%s]: filtering logs %d-%d: %v", e.chainCfg.Name, from, confirmedUpTo, err)
+		return
+	}
+
+	for _, l := range logs {
+		key := emittedKey{blockNumber: l.BlockNumber, txHash: l.TxHash, logIndex: l.Index}
+		if _, ok := e.emitted[key]; ok {
+			continue
+		}
+		e.emitted[key] = l.BlockHash
+		e.out <- Event{Log: l}
+	}
+
+	e.pruneEmitted(from)
+}
+
+// pruneEmitted drops tracked logs that have fallen out of the reorg
+// window, since they can no longer be invalidated.
+func (e *Engine) pruneEmitted(minHeight uint64) {
+	for key := range e.emitted {
+		if key.blockNumber < minHeight {
+			delete(e.emitted, key)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package indexer
+
+import "github.com/ethereum/go-ethereum/core/types"
+
+// Event is a single log emitted to downstream consumers once it has
+// cleared the engine's confirmation depth. Reverted is set instead of
+// removing the log outright so consumers that already persisted it can
+// react (e.g. mark a row invalidated) rather than silently losing track
+// of it.
+type Event struct {
+	Log      types.Log
+	Reverted bool
+}
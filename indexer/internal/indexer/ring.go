@@ -0,0 +1,57 @@
+package indexer
+
+import "github.com/ethereum/go-ethereum/core/types"
+
+// headerRing is a fixed-depth buffer of recently seen canonical headers,
+// ordered oldest to newest. It backs reorg detection: when an incoming
+// head's parent hash doesn't match the buffered tip, the engine walks the
+// ring backward looking for the fork point.
+type headerRing struct {
+	depth   uint64
+	headers []*types.Header
+}
+
+func newHeaderRing(depth uint64) *headerRing {
+	if depth == 0 {
+		depth = 1
+	}
+	return &headerRing{depth: depth}
+}
+
+func (r *headerRing) tip() *types.Header {
+	if len(r.headers) == 0 {
+		return nil
+	}
+	return r.headers[len(r.headers)-1]
+}
+
+func (r *headerRing) push(h *types.Header) {
+	r.headers = append(r.headers, h)
+	if uint64(len(r.headers)) > r.depth {
+		r.headers = r.headers[1:]
+	}
+}
+
+// at returns the buffered header at the given height, or nil if it has
+// fallen out of the window.
+func (r *headerRing) at(number uint64) *types.Header {
+	for _, h := range r.headers {
+		if h.Number.Uint64() == number {
+			return h
+		}
+	}
+	return nil
+}
+
+// truncateFrom drops every buffered header at height >= number. Used once
+// a reorg's fork point is found so stale headers past it aren't mistaken
+// for canonical on the next lookup.
+func (r *headerRing) truncateFrom(number uint64) {
+	kept := r.headers[:0]
+	for _, h := range r.headers {
+		if h.Number.Uint64() < number {
+			kept = append(kept, h)
+		}
+	}
+	r.headers = kept
+}
@@ -0,0 +1,60 @@
+package indexer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func header(n int64) *types.Header {
+	return &types.Header{Number: big.NewInt(n)}
+}
+
+func TestHeaderRingPushEvictsPastDepth(t *testing.T) {
+	r := newHeaderRing(3)
+	for i := int64(1); i <= 5; i++ {
+		r.push(header(i))
+	}
+	if got := r.tip().Number.Int64(); got != 5 {
+		t.Fatalf("tip = %d, want 5", got)
+	}
+	if r.at(2) != nil {
+		t.Fatalf("at(2) = %v, want nil (should have been evicted)", r.at(2))
+	}
+	if r.at(3) == nil {
+		t.Fatalf("at(3) = nil, want header 3 still buffered within depth")
+	}
+}
+
+func TestHeaderRingTruncateFromDropsForkedHeaders(t *testing.T) {
+	r := newHeaderRing(5)
+	for i := int64(1); i <= 5; i++ {
+		r.push(header(i))
+	}
+	r.truncateFrom(3)
+
+	for _, n := range []int64{3, 4, 5} {
+		if r.at(uint64(n)) != nil {
+			t.Fatalf("at(%d) = %v, want nil after truncateFrom(3)", n, r.at(uint64(n)))
+		}
+	}
+	if r.at(2) == nil {
+		t.Fatalf("at(2) = nil, truncateFrom(3) should not drop headers below the fork point")
+	}
+	if got := r.tip().Number.Int64(); got != 2 {
+		t.Fatalf("tip after truncateFrom(3) = %d, want 2", got)
+	}
+}
+
+func TestHeaderRingZeroDepthTreatedAsOne(t *testing.T) {
+	r := newHeaderRing(0)
+	r.push(header(1))
+	r.push(header(2))
+	if got := len(r.headers); got != 1 {
+		t.Fatalf("len(headers) = %d, want 1 for a zero-depth ring", got)
+	}
+	if got := r.tip().Number.Int64(); got != 2 {
+		t.Fatalf("tip = %d, want 2", got)
+	}
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// HighestBackfilled returns the highest block for which every log on
+// chain matching address has been durably committed, and whether any
+// progress has been recorded at all.
+func (s *Store) HighestBackfilled(ctx context.Context, chain, address string) (uint64, bool, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT to_block FROM backfill_progress WHERE chain = $1 AND address = $2
+	`, chain, address)
+
+	var toBlock uint64
+	err := row.Scan(&toBlock)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("store: reading backfill progress for %s/%s: %w", chain, address, err)
+	}
+	return toBlock, true, nil
+}
+
+// CommitBackfillChunk atomically inserts every event found in a single
+// backfilled block range and, if advanceProgress is set, advances the
+// recorded progress for every address in addresses to toBlock. Doing
+// both in one transaction is what lets a restart resume from exactly the
+// last committed range instead of re-scanning it (harmless, since
+// InsertEvent is idempotent) or, worse, skipping it because progress
+// advanced but the events didn't land. Callers pass advanceProgress=false
+// for a range that completed ahead of an earlier one still in flight:
+// its events are safe to insert now, but toBlock isn't yet a contiguous
+// frontier, so progress must wait for the gap to close.
+func (s *Store) CommitBackfillChunk(ctx context.Context, chain string, addresses []string, events []VaultEvent, toBlock uint64, advanceProgress bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("store: beginning backfill chunk transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, e := range events {
+		if err := insertEvent(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+
+	if advanceProgress {
+		for _, addr := range addresses {
+			// Workers commit chunks concurrently and may finish out of claim
+			// order, so only move progress forward, never back.
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO backfill_progress (chain, address, to_block, updated_at)
+				VALUES ($1, $2, $3, now())
+				ON CONFLICT (chain, address) DO UPDATE
+					SET to_block = GREATEST(backfill_progress.to_block, EXCLUDED.to_block), updated_at = now()
+			`, chain, addr, toBlock)
+			if err != nil {
+				return fmt.Errorf("store: recording backfill progress for %s/%s: %w", chain, addr, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: committing backfill chunk: %w", err)
+	}
+	return nil
+}
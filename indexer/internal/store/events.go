@@ -0,0 +1,189 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// VaultEvent is a single decoded Deposit/Withdraw/FeesCollected log
+// persisted by the indexer, keyed uniquely by (chain, tx hash, log
+// index) so re-emitting it on restart is a no-op.
+type VaultEvent struct {
+	ID           int64
+	Chain        string
+	Protocol     string
+	VaultAddress string
+	EventName    string
+	TxHash       string
+	LogIndex     int
+	BlockNumber  uint64
+	Caller       string
+	Receiver     string
+	Owner        string
+	Assets       *big.Int
+	Shares       *big.Int
+	Reverted     bool
+	RecordedAt   time.Time
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx, so insertEvent can run
+// either as its own statement or as part of a larger transaction (see
+// CommitBackfillChunk).
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertEvent upserts e via x. A log that was previously emitted and has
+// since been marked Reverted by the indexer's reorg handling updates the
+// existing row's reverted flag rather than inserting a duplicate.
+func insertEvent(ctx context.Context, x execer, e VaultEvent) error {
+	_, err := x.ExecContext(ctx, `
+		INSERT INTO vault_events
+			(chain, protocol, vault_address, event_name, tx_hash, log_index, block_number, caller, receiver, owner, assets, shares, reverted)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (chain, tx_hash, log_index) DO UPDATE SET reverted = EXCLUDED.reverted
+	`,
+		e.Chain, e.Protocol, e.VaultAddress, e.EventName, e.TxHash, e.LogIndex, e.BlockNumber,
+		nullString(e.Caller), nullString(e.Receiver), nullString(e.Owner),
+		nullBigInt(e.Assets), nullBigInt(e.Shares), e.Reverted,
+	)
+	if err != nil {
+		return fmt.Errorf("store: inserting vault event: %w", err)
+	}
+	return nil
+}
+
+// InsertEvent upserts e outside of any larger transaction.
+func (s *Store) InsertEvent(ctx context.Context, e VaultEvent) error {
+	return insertEvent(ctx, s.db, e)
+}
+
+// EventsSince returns every non-reverted vault event with id > afterID,
+// ordered oldest first, for the API server's WS pollers to fan out as
+// newDeposits/newWithdraws notifications.
+func (s *Store) EventsSince(ctx context.Context, afterID int64) ([]VaultEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, chain, protocol, vault_address, event_name, tx_hash, log_index, block_number,
+		       COALESCE(caller, ''), COALESCE(receiver, ''), COALESCE(owner, ''),
+		       COALESCE(assets::text, ''), COALESCE(shares::text, ''), reverted
+		FROM vault_events
+		WHERE id > $1 AND NOT reverted
+		ORDER BY id ASC
+	`, afterID)
+	if err != nil {
+		return nil, fmt.Errorf("store: querying events since %d: %w", afterID, err)
+	}
+	defer rows.Close()
+
+	var events []VaultEvent
+	for rows.Next() {
+		var e VaultEvent
+		var assets, shares string
+		if err := rows.Scan(&e.ID, &e.Chain, &e.Protocol, &e.VaultAddress, &e.EventName, &e.TxHash, &e.LogIndex,
+			&e.BlockNumber, &e.Caller, &e.Receiver, &e.Owner, &assets, &shares, &e.Reverted); err != nil {
+			return nil, fmt.Errorf("store: scanning vault event: %w", err)
+		}
+		e.Assets = parseBigInt(assets)
+		e.Shares = parseBigInt(shares)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// LastEvent returns the most recently recorded non-reverted event named
+// eventName for vaultAddress on chain, and whether one has ever been
+// recorded. The executor's harvest scanner uses this to decide whether a
+// vault's FeesCollected history is stale.
+func (s *Store) LastEvent(ctx context.Context, chain, vaultAddress, eventName string) (VaultEvent, bool, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, chain, protocol, vault_address, event_name, tx_hash, log_index, block_number,
+		       COALESCE(caller, ''), COALESCE(receiver, ''), COALESCE(owner, ''),
+		       COALESCE(assets::text, ''), COALESCE(shares::text, ''), reverted, recorded_at
+		FROM vault_events
+		WHERE chain = $1 AND vault_address = $2 AND event_name = $3 AND NOT reverted
+		ORDER BY block_number DESC
+		LIMIT 1
+	`, chain, vaultAddress, eventName)
+
+	var e VaultEvent
+	var assets, shares string
+	err := row.Scan(&e.ID, &e.Chain, &e.Protocol, &e.VaultAddress, &e.EventName, &e.TxHash, &e.LogIndex,
+		&e.BlockNumber, &e.Caller, &e.Receiver, &e.Owner, &assets, &shares, &e.Reverted, &e.RecordedAt)
+	if err == sql.ErrNoRows {
+		return VaultEvent{}, false, nil
+	}
+	if err != nil {
+		return VaultEvent{}, false, fmt.Errorf("store: querying last %s event for %s/%s: %w", eventName, chain, vaultAddress, err)
+	}
+	e.Assets = parseBigInt(assets)
+	e.Shares = parseBigInt(shares)
+	return e, true, nil
+}
+
+// UserPosition is a user's net share balance in a single vault, derived
+// from the event ledger rather than tracked separately.
+type UserPosition struct {
+	Chain        string
+	Protocol     string
+	VaultAddress string
+	NetShares    *big.Int
+}
+
+// UserPositions sums Deposit/Withdraw shares per vault for user on
+// chain, giving their current net position without needing a separate
+// balances table.
+func (s *Store) UserPositions(ctx context.Context, chain, user string) ([]UserPosition, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT protocol, vault_address,
+		       COALESCE(SUM(CASE
+		           WHEN event_name = 'Deposit' THEN shares
+		           WHEN event_name = 'Withdraw' THEN -shares
+		           ELSE 0
+		       END), 0)::text AS net_shares
+		FROM vault_events
+		WHERE chain = $1 AND owner = $2 AND NOT reverted
+		GROUP BY protocol, vault_address
+	`, chain, user)
+	if err != nil {
+		return nil, fmt.Errorf("store: querying user positions: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []UserPosition
+	for rows.Next() {
+		var p UserPosition
+		var netShares string
+		p.Chain = chain
+		if err := rows.Scan(&p.Protocol, &p.VaultAddress, &netShares); err != nil {
+			return nil, fmt.Errorf("store: scanning user position: %w", err)
+		}
+		p.NetShares = parseBigInt(netShares)
+		positions = append(positions, p)
+	}
+	return positions, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullBigInt(v *big.Int) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: v.String(), Valid: true}
+}
+
+func parseBigInt(s string) *big.Int {
+	if s == "" {
+		return big.NewInt(0)
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return v
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// VaultSnapshot is a single point-in-time reading of a vault's
+// share/asset accounting, as produced by adapter.Adapter.Snapshot.
+type VaultSnapshot struct {
+	Chain         string
+	Protocol      string
+	VaultAddress  string
+	BlockNumber   uint64
+	TotalAssets   *big.Int
+	TotalSupply   *big.Int
+	PricePerShare *big.Int
+	RecordedAt    time.Time
+}
+
+// InsertSnapshot records snap. Re-recording the same (chain, vault,
+// block) is a no-op so snapshotting the same block twice (e.g. after a
+// restart) can't duplicate history.
+func (s *Store) InsertSnapshot(ctx context.Context, snap VaultSnapshot) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO vault_snapshots (chain, protocol, vault_address, block_number, total_assets, total_supply, price_per_share)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (chain, vault_address, block_number) DO NOTHING
+	`, snap.Chain, snap.Protocol, snap.VaultAddress, snap.BlockNumber,
+		snap.TotalAssets.String(), snap.TotalSupply.String(), snap.PricePerShare.String())
+	if err != nil {
+		return fmt.Errorf("store: inserting vault snapshot: %w", err)
+	}
+	return nil
+}
+
+// VaultState returns the most recently recorded snapshot for vaultAddress
+// on chain.
+func (s *Store) VaultState(ctx context.Context, chain, vaultAddress string) (VaultSnapshot, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT protocol, vault_address, block_number, total_assets::text, total_supply::text, price_per_share::text, recorded_at
+		FROM vault_snapshots
+		WHERE chain = $1 AND vault_address = $2
+		ORDER BY block_number DESC
+		LIMIT 1
+	`, chain, vaultAddress)
+
+	var snap VaultSnapshot
+	var totalAssets, totalSupply, pricePerShare string
+	snap.Chain = chain
+	err := row.Scan(&snap.Protocol, &snap.VaultAddress, &snap.BlockNumber, &totalAssets, &totalSupply, &pricePerShare, &snap.RecordedAt)
+	if err == sql.ErrNoRows {
+		return VaultSnapshot{}, fmt.Errorf("store: no snapshot recorded for %s on %s", vaultAddress, chain)
+	}
+	if err != nil {
+		return VaultSnapshot{}, fmt.Errorf("store: querying vault state: %w", err)
+	}
+	snap.TotalAssets = parseBigInt(totalAssets)
+	snap.TotalSupply = parseBigInt(totalSupply)
+	snap.PricePerShare = parseBigInt(pricePerShare)
+	return snap, nil
+}
+
+// TVLHistory returns every snapshot for vaultAddress on chain recorded
+// between from and to, ordered oldest first.
+func (s *Store) TVLHistory(ctx context.Context, chain, vaultAddress string, from, to time.Time) ([]VaultSnapshot, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT protocol, vault_address, block_number, total_assets::text, total_supply::text, price_per_share::text, recorded_at
+		FROM vault_snapshots
+		WHERE chain = $1 AND vault_address = $2 AND recorded_at BETWEEN $3 AND $4
+		ORDER BY block_number ASC
+	`, chain, vaultAddress, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("store: querying tvl history: %w", err)
+	}
+	defer rows.Close()
+
+	var snaps []VaultSnapshot
+	for rows.Next() {
+		var snap VaultSnapshot
+		var totalAssets, totalSupply, pricePerShare string
+		snap.Chain = chain
+		if err := rows.Scan(&snap.Protocol, &snap.VaultAddress, &snap.BlockNumber, &totalAssets, &totalSupply, &pricePerShare, &snap.RecordedAt); err != nil {
+			return nil, fmt.Errorf("store: scanning vault snapshot: %w", err)
+		}
+		snap.TotalAssets = parseBigInt(totalAssets)
+		snap.TotalSupply = parseBigInt(totalSupply)
+		snap.PricePerShare = parseBigInt(pricePerShare)
+		snaps = append(snaps, snap)
+	}
+	return snaps, rows.Err()
+}
+
+// VaultRef identifies a single configured vault contract.
+type VaultRef struct {
+	Chain        string
+	Protocol     string
+	VaultAddress string
+}
+
+// ListVaults returns every (chain, vault) pair with at least one
+// recorded snapshot, for callers that need to poll each vault's latest
+// state without knowing the configured set up front.
+func (s *Store) ListVaults(ctx context.Context) ([]VaultRef, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT chain, protocol, vault_address FROM vault_snapshots
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("store: listing vaults: %w", err)
+	}
+	defer rows.Close()
+
+	var refs []VaultRef
+	for rows.Next() {
+		var ref VaultRef
+		if err := rows.Scan(&ref.Chain, &ref.Protocol, &ref.VaultAddress); err != nil {
+			return nil, fmt.Errorf("store: scanning vault ref: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+// ListProtocols returns every distinct protocol name with at least one
+// recorded event or snapshot.
+func (s *Store) ListProtocols(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT protocol FROM vault_snapshots
+		UNION
+		SELECT DISTINCT protocol FROM vault_events
+		ORDER BY protocol
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("store: listing protocols: %w", err)
+	}
+	defer rows.Close()
+
+	var protocols []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, fmt.Errorf("store: scanning protocol: %w", err)
+		}
+		protocols = append(protocols, p)
+	}
+	return protocols, rows.Err()
+}
@@ -0,0 +1,39 @@
+package tracer
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// InternalTransfer is a value flow found inside a traced transaction's
+// call tree: either a native-asset transfer (Token is the zero address)
+// or a decoded ERC-20 transfer/transferFrom call.
+type InternalTransfer struct {
+	From   common.Address
+	To     common.Address
+	Token  common.Address
+	Amount *big.Int
+}
+
+// SubCall is a single call frame the traced transaction made, recording
+// the address it touched, the function selector it invoked, and whether
+// that sub-call reverted.
+type SubCall struct {
+	To       common.Address
+	Selector [4]byte
+	Success  bool
+	Error    string
+}
+
+// TracedEvent carries a matched vault log plus the flattened call-tree
+// enrichment extracted from its transaction: the strategy/adapter
+// addresses actually touched (SubCalls), the ERC-20/native value flows
+// between the vault and those addresses (InternalTransfers), and any
+// revert reasons on failed sub-calls (SubCall.Error).
+type TracedEvent struct {
+	Log               types.Log
+	InternalTransfers []InternalTransfer
+	SubCalls          []SubCall
+}
@@ -0,0 +1,125 @@
+// Package tracer enriches a matched vault log with the call tree behind
+// its transaction, obtained via debug_traceTransaction's callTracer, so
+// callers can attribute yield correctly when a vault fans funds out to
+// several strategies in one transaction.
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// traceTimeout bounds a single debug_traceTransaction call so a slow or
+// unresponsive node can't stall the caller indefinitely.
+const traceTimeout = 10 * time.Second
+
+var (
+	erc20TransferSelector     = crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	erc20TransferFromSelector = crypto.Keccak256([]byte("transferFrom(address,address,uint256)"))[:4]
+)
+
+// Tracer walks the call tree of the transactions behind matched vault
+// logs. It's optional per chain (gated by ChainConfig.TracingEnabled)
+// since many public RPCs disable the debug_* namespace.
+type Tracer struct {
+	client *ethclient.Client
+}
+
+// New builds a Tracer that issues debug_* calls against client's
+// underlying RPC connection.
+func New(client *ethclient.Client) *Tracer {
+	return &Tracer{client: client}
+}
+
+// Trace calls debug_traceTransaction with the callTracer config for the
+// transaction behind log and flattens the resulting call tree into a
+// TracedEvent.
+func (t *Tracer) Trace(ctx context.Context, log types.Log) (TracedEvent, error) {
+	traceCtx, cancel := context.WithTimeout(ctx, traceTimeout)
+	defer cancel()
+
+	var root callFrame
+	err := t.client.Client().CallContext(traceCtx, &root, "debug_traceTransaction", log.TxHash, map[string]interface{}{
+		"tracer": "callTracer",
+	})
+	if err != nil {
+		return TracedEvent{}, fmt.Errorf("tracer: debug_traceTransaction %s: %w", log.TxHash, err)
+	}
+
+	event := TracedEvent{Log: log}
+	walkCallFrame(&root, &event)
+	return event, nil
+}
+
+// walkCallFrame recurses into frame's call tree, recording every sub-call
+// touched, every ERC-20 transfer/transferFrom it can decode from a call's
+// input, and every native-value transfer.
+func walkCallFrame(frame *callFrame, event *TracedEvent) {
+	if frame == nil {
+		return
+	}
+
+	switch frame.Type {
+	case "CALL", "STATICCALL", "DELEGATECALL", "CALLCODE":
+		var selector [4]byte
+		if len(frame.Input) >= 4 {
+			copy(selector[:], frame.Input[:4])
+		}
+		event.SubCalls = append(event.SubCalls, SubCall{
+			To:       frame.To,
+			Selector: selector,
+			Success:  frame.Error == "",
+			Error:    frame.Error,
+		})
+
+		if transfer, ok := decodeERC20Transfer(frame); ok {
+			event.InternalTransfers = append(event.InternalTransfers, transfer)
+		}
+	}
+
+	if frame.Value != nil && frame.Value.ToInt().Sign() > 0 {
+		event.InternalTransfers = append(event.InternalTransfers, InternalTransfer{
+			From:   frame.From,
+			To:     frame.To,
+			Token:  common.Address{}, // zero address denotes the native asset
+			Amount: frame.Value.ToInt(),
+		})
+	}
+
+	for i := range frame.Calls {
+		walkCallFrame(&frame.Calls[i], event)
+	}
+}
+
+// decodeERC20Transfer recognizes an ERC-20 transfer/transferFrom call by
+// its 4-byte selector and decodes the moved value flow it represents.
+func decodeERC20Transfer(frame *callFrame) (InternalTransfer, bool) {
+	if len(frame.Input) < 4 {
+		return InternalTransfer{}, false
+	}
+	selector := frame.Input[:4]
+
+	switch {
+	case bytes.Equal(selector, erc20TransferSelector) && len(frame.Input) >= 4+64:
+		to := common.BytesToAddress(frame.Input[4:36])
+		amount := new(big.Int).SetBytes(frame.Input[36:68])
+		return InternalTransfer{From: frame.From, To: to, Token: frame.To, Amount: amount}, true
+
+	case bytes.Equal(selector, erc20TransferFromSelector) && len(frame.Input) >= 4+96:
+		from := common.BytesToAddress(frame.Input[4:36])
+		to := common.BytesToAddress(frame.Input[36:68])
+		amount := new(big.Int).SetBytes(frame.Input[68:100])
+		return InternalTransfer{From: from, To: to, Token: frame.To, Amount: amount}, true
+
+	default:
+		return InternalTransfer{}, false
+	}
+}